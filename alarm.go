@@ -0,0 +1,110 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// GetEventInformation returns the summaries of the objects of a device that
+// have an active event or unacknowledged transitions. Devices return the
+// summaries in pages, the following pages are requested until every summary
+// has been read.
+func (c *Client) GetEventInformation(dev bactype.Device) ([]bactype.EventSummary, error) {
+	var out []bactype.EventSummary
+	var req bactype.GetEventInformation
+	for {
+		page, err := c.getEventInformation(dev, req)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, page.Summaries...)
+
+		// A page without summaries would request the same page again
+		if !page.MoreEvents || len(page.Summaries) == 0 {
+			return out, nil
+		}
+		last := page.Summaries[len(page.Summaries)-1].Object
+		req.LastObject = &last
+	}
+}
+
+func (c *Client) getEventInformation(dev bactype.Device, req bactype.GetEventInformation) (bactype.GetEventInformationAck, error) {
+	var out bactype.GetEventInformationAck
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedGetEventInformation, func(enc *encoding.Encoder, id uint8) error {
+		return enc.GetEventInformation(id, req)
+	})
+	if err != nil {
+		return out, err
+	}
+	err = dec.GetEventInformationAck(&out)
+	return out, err
+}
+
+// AcknowledgeAlarm acknowledges an event transition of an object, for example
+// one received through SubscribeEvents or listed by GetEventInformation. Use
+// types.NewTimeStamp(time.Now()) as the time of acknowledgment.
+func (c *Client) AcknowledgeAlarm(dev bactype.Device, ack bactype.AcknowledgeAlarm) error {
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.AcknowledgeAlarm(id, ack)
+	})
+}
+
+// GetAlarmSummary returns the objects of a device that are in an alarm state.
+// It is superseded by GetEventInformation but is all that older devices
+// support.
+func (c *Client) GetAlarmSummary(dev bactype.Device) ([]bactype.AlarmSummary, error) {
+	var out []bactype.AlarmSummary
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedGetAlarmSummary, func(enc *encoding.Encoder, id uint8) error {
+		return enc.GetAlarmSummary(id)
+	})
+	if err != nil {
+		return out, err
+	}
+	err = dec.GetAlarmSummaryAck(&out)
+	return out, err
+}
+
+// GetEnrollmentSummary returns the event enrollments of a device that match
+// the filters of the request.
+func (c *Client) GetEnrollmentSummary(dev bactype.Device, filter bactype.GetEnrollmentSummary) ([]bactype.EnrollmentSummary, error) {
+	var out []bactype.EnrollmentSummary
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedGetEnrollmentSummary, func(enc *encoding.Encoder, id uint8) error {
+		return enc.GetEnrollmentSummary(id, filter)
+	})
+	if err != nil {
+		return out, err
+	}
+	err = dec.GetEnrollmentSummaryAck(&out)
+	return out, err
+}
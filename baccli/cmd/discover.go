@@ -84,7 +84,7 @@ func discover(cmd *cobra.Command, args []string) {
 			for devs := range scan {
 				for _, d := range devs {
 					log.Infof("Found device: %d", d.ID.Instance)
-					dev, err := c.Objects(d)
+					dev, err := c.ObjectsWithValues(d)
 
 					if err != nil {
 						log.Error(err)
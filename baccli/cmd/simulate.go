@@ -0,0 +1,82 @@
+// Copyright © 2017 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and // limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+
+	"github.com/alexbeltran/gobacnet"
+	"github.com/alexbeltran/gobacnet/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var simulateInput string
+
+// simulateCmd represents the simulate command
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "simulate serves the devices saved by discover",
+	Long: `simulate loads the devices saved by discover and serves a replica of
+ each device until interrupted, with the names, descriptions and values that
+ discover captured. The first device listens on the given port and
+ every other device on the next port up, so only the first device answers
+ broadcast who is requests on the standard port.`,
+	Run: simulate,
+}
+
+func simulate(cmd *cobra.Command, args []string) {
+	log := logrus.New()
+	log.Formatter = &logrus.TextFormatter{}
+	log.Out = os.Stdout
+
+	f, err := os.Open(simulateInput)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var devices []types.Device
+	err = json.NewDecoder(f).Decode(&devices)
+	f.Close()
+	if err != nil {
+		log.Fatalf("unable to load %s: %v", simulateInput, err)
+	}
+
+	clients, err := gobacnet.Simulate(Interface, Port, devices)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}()
+
+	for i, c := range clients {
+		log.Infof("Simulating device %d with %d objects on port %d", devices[i].ID.Instance,
+			len(c.Store().Objects())-1, Port+i)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	<-stop
+	log.Info("Stopping simulation")
+}
+
+func init() {
+	RootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().StringVarP(&simulateInput, "input", "f", "out.json", "Devices to simulate as saved by discover")
+}
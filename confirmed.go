@@ -0,0 +1,420 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// maxSegments is the number of segments we accept in a segmented response.
+// Responses with more segments are aborted.
+const maxSegments = 64
+
+// serviceEncoder encodes a confirmed service request using the given invoke id.
+type serviceEncoder func(enc *encoding.Encoder, invokeID uint8) error
+
+// encodeRequest encodes the service request for the device with the given
+// invoke id. The npdu makes up the first npduLen bytes of the request.
+func (c *Client) encodeRequest(dev bactype.Device, invokeID uint8, service serviceEncoder) (pack []byte, npduLen int, err error) {
+	udp, err := c.localUDPAddress()
+	if err != nil {
+		return nil, 0, err
+	}
+	src := bactype.UDPToAddress(udp)
+
+	enc := encoding.NewEncoder()
+	enc.NPDU(bactype.NPDU{
+		Version:               bactype.ProtocolVersion,
+		Destination:           &dev.Addr,
+		Source:                &src,
+		IsNetworkLayerMessage: false,
+		ExpectingReply:        true,
+		Priority:              bactype.Normal,
+		HopCount:              bactype.DefaultHopCount,
+	})
+	npduLen = len(enc.Bytes())
+	enc.AcceptSegmentedResponse(maxSegments)
+	if err = service(enc, invokeID); err != nil {
+		return nil, 0, fmt.Errorf("encoding request failed: %v", err)
+	}
+	return enc.Bytes(), npduLen, nil
+}
+
+// confirmedRequest sends the service request built by service to the device
+// and waits for the reply. The returned decoder is positioned right after the
+// APDU header so the caller can decode the service specific data of the reply.
+func (c *Client) confirmedRequest(dev bactype.Device, service serviceEncoder) (bactype.APDU, *encoding.Decoder, error) {
+	var apdu bactype.APDU
+
+	// Requests that do not fit into a single apdu of the device are sent in
+	// segments. The segmentation of the device is looked up before we take a
+	// transaction id since the lookup needs transaction ids of its own. The
+	// size of the request does not depend on the invoke id.
+	pack, npduLen, err := c.encodeRequest(dev, 0, service)
+	if err != nil {
+		return apdu, nil, err
+	}
+	segmented := dev.MaxApdu != 0 && dev.MaxApdu < uint32(len(pack)-npduLen)
+	if segmented {
+		dev, err = c.segmentation(dev)
+		if err != nil {
+			return apdu, nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	id, err := c.tsm.ID(ctx)
+	if err != nil {
+		return apdu, nil, fmt.Errorf("unable to get transaction id: %v", err)
+	}
+	defer c.tsm.Put(id)
+
+	if pack, npduLen, err = c.encodeRequest(dev, uint8(id), service); err != nil {
+		return apdu, nil, err
+	}
+	var segments [][]byte
+	if segmented {
+		segments, err = segmentRequest(dev, pack[:npduLen], pack[npduLen:])
+		if err != nil {
+			return apdu, nil, err
+		}
+	}
+
+	for count := 0; count < maxReattempt; count++ {
+		var raw interface{}
+		if segments != nil {
+			raw, err = c.sendSegments(dev, id, segments)
+		} else {
+			_, err = c.send(dev.Addr, pack)
+			if err != nil {
+				continue
+			}
+			raw, err = c.tsm.Receive(id, time.Duration(5)*time.Second)
+		}
+		if err != nil {
+			continue
+		}
+
+		switch v := raw.(type) {
+		case error:
+			return apdu, nil, v
+		case []byte:
+			dec := encoding.NewDecoder(v)
+			if err = dec.APDU(&apdu); err != nil {
+				return apdu, nil, err
+			}
+			return apdu, dec, nil
+		default:
+			return apdu, nil, fmt.Errorf("received unknown datatype %T", raw)
+		}
+	}
+	return apdu, nil, &timeoutError{tries: maxReattempt, err: err}
+}
+
+// segmentationCache holds the segmentation that was read from the devices,
+// keyed by their address.
+type segmentationCache struct {
+	mutex   sync.Mutex
+	devices map[string]bactype.Device
+}
+
+// segmentation fills in the Segmentation_Supported and Max_Segments_Accepted
+// of the device before a request is segmented. The values of the caller are
+// used when they are known. Both are unknown for devices that were built by
+// hand rather than found with WhoIs, where the zero Segmentation would claim
+// that segmented requests are accepted, and I-Am does not carry the max
+// segments at all. The values that had to be read are cached per device.
+func (c *Client) segmentation(dev bactype.Device) (bactype.Device, error) {
+	if dev.MaxSegments != 0 {
+		return dev, nil
+	}
+	if dev.Segmentation != bactype.SegmentationBoth && !dev.AcceptsSegmentedRequests() {
+		// The request will not be segmented, so the max segments do not matter
+		return dev, nil
+	}
+	key := fmt.Sprint(dev.Addr)
+
+	c.segmentations.mutex.Lock()
+	cached, ok := c.segmentations.devices[key]
+	c.segmentations.mutex.Unlock()
+	if !ok {
+		var err error
+		cached, err = c.readSegmentation(dev, dev.Segmentation == bactype.SegmentationBoth)
+		if err != nil {
+			return dev, err
+		}
+		c.segmentations.mutex.Lock()
+		if c.segmentations.devices == nil {
+			c.segmentations.devices = make(map[string]bactype.Device)
+		}
+		c.segmentations.devices[key] = cached
+		c.segmentations.mutex.Unlock()
+	}
+
+	if dev.Segmentation == bactype.SegmentationBoth {
+		dev.Segmentation = cached.Segmentation
+	}
+	dev.MaxSegments = cached.MaxSegments
+	return dev, nil
+}
+
+// readSegmentation reads the Segmentation_Supported, when it is not known, and
+// Max_Segments_Accepted of the device. Devices that predate
+// Max_Segments_Accepted accept any number of segments.
+func (c *Client) readSegmentation(dev bactype.Device, readSegmentation bool) (bactype.Device, error) {
+	id := dev.ID
+	if id.Type != bactype.DeviceType {
+		id = bactype.ObjectID{Type: bactype.DeviceType, Instance: bactype.MaxInstance}
+	}
+	read := func(prop uint32) (uint32, error) {
+		rp, err := c.ReadProperty(dev, bactype.ReadPropertyData{
+			Object: bactype.Object{
+				ID:         id,
+				Properties: []bactype.Property{{Type: prop, ArrayIndex: bactype.ArrayAll}},
+			},
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(rp.Object.Properties) == 0 {
+			return 0, fmt.Errorf("no data was returned")
+		}
+		switch v := rp.Object.Properties[0].Data.(type) {
+		case uint32:
+			return v, nil
+		case bactype.Enumerated:
+			return uint32(v), nil
+		}
+		return 0, fmt.Errorf("expected an unsigned value, got %T", rp.Object.Properties[0].Data)
+	}
+
+	if readSegmentation {
+		segmentation, err := read(property.SegmentationSupported)
+		if err != nil {
+			return dev, fmt.Errorf("unable to read the segmentation supported by the device: %v", err)
+		}
+		dev.Segmentation = bactype.Enumerated(segmentation)
+		if !dev.AcceptsSegmentedRequests() {
+			return dev, nil
+		}
+	}
+
+	max, err := read(property.MaxSegmentsAccepted)
+	switch {
+	case err == nil:
+		dev.MaxSegments = uint(max)
+	case errors.Is(err, ErrUnknownProperty):
+		dev.MaxSegments = 0
+	default:
+		return dev, fmt.Errorf("unable to read the max segments accepted by the device: %v", err)
+	}
+	return dev, nil
+}
+
+// segmentedHeaderLen is the length of the apdu header of a segment of a
+// confirmed request.
+const segmentedHeaderLen = 6
+
+// proposedWindowSize is the number of segments we would like to send before
+// waiting for an ack. The device decides on the actual window size.
+const proposedWindowSize = 16
+
+// segmentRequest splits an encoded confirmed request into the segments that
+// are sent to the device. Each segment holds the npdu followed by the segmented
+// apdu.
+func segmentRequest(dev bactype.Device, npdu []byte, request []byte) ([][]byte, error) {
+	if !dev.AcceptsSegmentedRequests() {
+		return nil, fmt.Errorf("request is too large (max: %d given: %d) and the device does not accept segmented requests",
+			dev.MaxApdu, len(request))
+	}
+	if dev.MaxApdu <= segmentedHeaderLen {
+		return nil, fmt.Errorf("max apdu of %d is too small for a segmented request", dev.MaxApdu)
+	}
+
+	var a bactype.APDU
+	if err := encoding.NewDecoder(request).APDU(&a); err != nil {
+		return nil, err
+	}
+
+	size := int(dev.MaxApdu) - segmentedHeaderLen
+	count := (len(a.RawData) + size - 1) / size
+	if dev.MaxSegments != 0 && uint(count) > dev.MaxSegments {
+		return nil, fmt.Errorf("request needs %d segments but the device accepts %d", count, dev.MaxSegments)
+	}
+
+	segments := make([][]byte, count)
+	for i := range segments {
+		end := (i + 1) * size
+		if end > len(a.RawData) {
+			end = len(a.RawData)
+		}
+
+		enc := encoding.NewEncoder()
+		enc.APDU(bactype.APDU{
+			DataType:                  bactype.ConfirmedServiceRequest,
+			SegmentedMessage:          true,
+			MoreFollows:               i < count-1,
+			SegmentedResponseAccepted: a.SegmentedResponseAccepted,
+			MaxSegs:                   a.MaxSegs,
+			MaxApdu:                   a.MaxApdu,
+			InvokeId:                  a.InvokeId,
+			Sequence:                  uint8(i),
+			WindowNumber:              proposedWindowSize,
+			Service:                   a.Service,
+		})
+		if err := enc.Error(); err != nil {
+			return nil, err
+		}
+		seg := append([]byte(nil), npdu...)
+		seg = append(seg, enc.Bytes()...)
+		segments[i] = append(seg, a.RawData[i*size:end]...)
+	}
+	return segments, nil
+}
+
+// sendSegments sends the segments of a request a window at a time and returns
+// the reply of the device once every segment has been acknowledged. The first
+// segment is sent on its own since its ack tells us the window size of the
+// device.
+func (c *Client) sendSegments(dev bactype.Device, id int, segments [][]byte) (interface{}, error) {
+	timeout := time.Duration(5) * time.Second
+	window := 1
+	next := 0
+	tries := 0
+	for next < len(segments) {
+		end := next + window
+		if end > len(segments) {
+			end = len(segments)
+		}
+		for _, seg := range segments[next:end] {
+			if _, err := c.send(dev.Addr, seg); err != nil {
+				return nil, err
+			}
+		}
+
+		ack, reply, err := c.tsm.ReceiveAck(id, timeout)
+		if err != nil {
+			// Send the window again
+			tries++
+			if tries >= maxReattempt {
+				return nil, err
+			}
+			continue
+		}
+		if reply != nil {
+			// The device answered before every segment was sent, which
+			// happens when it rejects or aborts the request.
+			return reply, nil
+		}
+		tries = 0
+
+		// Sequence numbers are the segment index modulo 256. An ack for the
+		// segment before the window, which is a negative ack that nothing
+		// arrived, starts the window over.
+		if ack.Sequence != uint8(next-1) {
+			acked := int(ack.Sequence - uint8(next))
+			if acked >= end-next {
+				// Ack of an earlier window that arrived late
+				continue
+			}
+			next += acked + 1
+		}
+
+		window = int(ack.WindowSize)
+		if window < 1 {
+			window = 1
+		}
+	}
+	return c.tsm.Receive(id, timeout)
+}
+
+// timeoutError is returned when a device did not reply to any of the attempts
+// of a confirmed request.
+type timeoutError struct {
+	tries int
+	err   error
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("failed %d tries: %v", e.tries, e.err)
+}
+
+// Timeout reports that the request timed out, like net.Error does.
+func (e *timeoutError) Timeout() bool {
+	return true
+}
+
+// isTimeout checks to see if the request failed because the device did not
+// reply, in which case it is safe to send the request again.
+func isTimeout(err error) bool {
+	t, ok := err.(interface {
+		Timeout() bool
+	})
+	return ok && t.Timeout()
+}
+
+// simpleAckRequest sends a confirmed service request where the device is
+// expected to only acknowledge the request.
+func (c *Client) simpleAckRequest(dev bactype.Device, service serviceEncoder) error {
+	apdu, _, err := c.confirmedRequest(dev, service)
+	if err != nil {
+		return err
+	}
+	if apdu.DataType != bactype.SimpleAck {
+		return fmt.Errorf("expected a simple ack but received pdu type %d", apdu.DataType)
+	}
+	return nil
+}
+
+// complexAckRequest sends a confirmed service request where the device is
+// expected to reply with a complex ack of the same service. The returned
+// decoder is positioned at the service data of the ack.
+func (c *Client) complexAckRequest(dev bactype.Device, service bactype.ServiceConfirmed, request serviceEncoder) (*encoding.Decoder, error) {
+	apdu, dec, err := c.confirmedRequest(dev, request)
+	if err != nil {
+		return nil, err
+	}
+	if apdu.DataType != bactype.ComplexAck || apdu.Service != service {
+		return nil, fmt.Errorf("expected a complex ack of service %d but received pdu type %d service %d",
+			service, apdu.DataType, apdu.Service)
+	}
+	return dec, nil
+}
@@ -47,4 +47,7 @@ const defautlMaxInfoFrames = 1
 // ArrayAll is used when reading/writting to a property to read/write the entire
 // array
 const ArrayAll = 0xFFFFFFFF
+
+// NoPriority is used when writing to a property that is not commandable
+const NoPriority = 0
 const maxStandardBacnetType = 128
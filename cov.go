@@ -0,0 +1,189 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// lifetimeSeconds converts the lifetime of a subscription to seconds
+func lifetimeSeconds(lifetime time.Duration) (uint32, error) {
+	if lifetime < 0 || (lifetime > 0 && lifetime < time.Second) {
+		return 0, fmt.Errorf("lifetime must be 0 or at least a second, got %v", lifetime)
+	}
+	return uint32(lifetime / time.Second), nil
+}
+
+// SubscribeCOV subscribes to the change of value notifications of an object in
+// the given device. Notifications are delivered on the returned channel until
+// the subscription is removed with UnsubscribeCOV or the client is closed, at
+// which point the channel is closed. The device sends confirmed notifications
+// when confirmed is set. A lifetime of 0 subscribes indefinitely, otherwise the
+// subscription is renewed automatically before the lifetime expires.
+func (c *Client) SubscribeCOV(dev bactype.Device, obj bactype.ObjectID, confirmed bool, lifetime time.Duration) (<-chan bactype.COVNotification, error) {
+	seconds, err := lifetimeSeconds(lifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := c.cov.new(nil, func(processID uint32) func(cancel bool) error {
+		req := bactype.SubscribeCOV{
+			ProcessID:      processID,
+			Object:         obj,
+			IssueConfirmed: confirmed,
+			Lifetime:       seconds,
+		}
+		return func(cancel bool) error {
+			r := req
+			r.Cancel = cancel
+			return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+				return enc.SubscribeCOV(id, r)
+			})
+		}
+	})
+	return c.startCOV(sub, lifetime)
+}
+
+// startCOV sends the initial subscription and keeps it alive
+func (c *Client) startCOV(sub *subscription[bactype.COVNotification], lifetime time.Duration) (<-chan bactype.COVNotification, error) {
+	if err := sub.subscribe(false); err != nil {
+		c.cov.remove(sub.id)
+		sub.stop()
+		return nil, fmt.Errorf("unable to subscribe: %v", err)
+	}
+	if lifetime > 0 {
+		go c.renewCOV(sub, lifetime)
+	}
+	return sub.data, nil
+}
+
+// renewCOV renews the subscription before its lifetime expires until the
+// subscription is stopped.
+func (c *Client) renewCOV(sub *subscription[bactype.COVNotification], lifetime time.Duration) {
+	ticker := time.NewTicker(lifetime * 3 / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-ticker.C:
+			if err := sub.subscribe(false); err != nil {
+				c.log.Errorf("unable to renew cov subscription %d: %v", sub.id, err)
+			}
+		}
+	}
+}
+
+// UnsubscribeCOV cancels the subscription that delivers notifications to the
+// given channel. The channel is closed.
+func (c *Client) UnsubscribeCOV(ch <-chan bactype.COVNotification) error {
+	sub := c.cov.removeChannel(ch)
+	if sub == nil {
+		return fmt.Errorf("channel does not belong to a cov subscription")
+	}
+	return sub.cancel()
+}
+
+// handleCOVNotification passes a notification sent by a device to the
+// matching subscription.
+func (c *Client) handleCOVNotification(b []byte) error {
+	var n bactype.COVNotification
+	dec := encoding.NewDecoder(b)
+	if err := dec.COVNotification(&n); err != nil {
+		return fmt.Errorf("unable to decode cov notification: %v", err)
+	}
+
+	sub := c.cov.get(n.ProcessID)
+	if sub == nil {
+		c.log.Debugf("cov notification for unknown subscription %d", n.ProcessID)
+		return nil
+	}
+	if !sub.deliver(n) {
+		c.log.Debugf("dropped cov notification for subscription %d, the subscriber is not keeping up", n.ProcessID)
+	}
+	return nil
+}
+
+// closeCOV cancels all subscriptions on the devices
+func (c *Client) closeCOV() {
+	var wg sync.WaitGroup
+	for _, sub := range c.cov.removeAll() {
+		wg.Add(1)
+		go func(sub *subscription[bactype.COVNotification]) {
+			defer wg.Done()
+			if err := sub.cancel(); err != nil {
+				c.log.Errorf("unable to cancel cov subscription %d: %v", sub.id, err)
+			}
+		}(sub)
+	}
+	wg.Wait()
+}
+
+// SubscribeCOVProperty subscribes to the change of value notifications of a
+// single property. Unlike SubscribeCOV, any property can be monitored such as
+// the reliability of an object. Increment is the minimum change of an analog
+// property before the device sends a notification; when nil the device's
+// COV_Increment is used. Notifications are delivered the same way as
+// SubscribeCOV and the subscription is removed with UnsubscribeCOV.
+func (c *Client) SubscribeCOVProperty(dev bactype.Device, ref bactype.ObjectPropertyReference, increment *float32, confirmed bool, lifetime time.Duration) (<-chan bactype.COVNotification, error) {
+	seconds, err := lifetimeSeconds(lifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := c.cov.new(nil, func(processID uint32) func(cancel bool) error {
+		req := bactype.SubscribeCOVProperty{
+			SubscribeCOV: bactype.SubscribeCOV{
+				ProcessID:      processID,
+				Object:         ref.Object,
+				IssueConfirmed: confirmed,
+				Lifetime:       seconds,
+			},
+			Property:     ref.Property,
+			ArrayIndex:   ref.ArrayIndex,
+			COVIncrement: increment,
+		}
+		return func(cancel bool) error {
+			r := req
+			r.Cancel = cancel
+			return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+				return enc.SubscribeCOVProperty(id, r)
+			})
+		}
+	})
+	return c.startCOV(sub, lifetime)
+}
@@ -0,0 +1,358 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/property"
+	"github.com/alexbeltran/gobacnet/store"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// maxCOVSubscribers is the number of cov subscriptions other devices can hold
+// on the local device at once.
+const maxCOVSubscribers = 256
+
+// covSubscriber is a change of value subscription another device holds on one
+// of our objects.
+type covSubscriber struct {
+	addr      bactype.Address
+	processID uint32
+	object    bactype.ObjectID
+	confirmed bool
+
+	// expires is when the subscription ends, or zero if it never does
+	expires time.Time
+
+	// property is the monitored property, which is the present value for
+	// subscriptions made with subscribe cov. Increment overrides the
+	// COV_Increment of the object when set.
+	property   uint32
+	arrayIndex uint32
+	increment  *float32
+
+	// last are the values of the last notification
+	last []bactype.Property
+}
+
+// same returns if both subscriptions were made by the same subscriber for the
+// same property, in which case one replaces the other.
+func (s *covSubscriber) same(other *covSubscriber) bool {
+	return s.processID == other.processID && s.object == other.object &&
+		s.property == other.property && s.arrayIndex == other.arrayIndex &&
+		sameAddress(s.addr, other.addr)
+}
+
+func sameAddress(a, b bactype.Address) bool {
+	return a.Net == b.Net && bytes.Equal(a.Mac, b.Mac) && bytes.Equal(a.Adr, b.Adr)
+}
+
+// values reads the monitored property along with the status flags of the
+// object, if it has any.
+func (s *covSubscriber) values(objects *store.Store) ([]bactype.Property, error) {
+	value, err := objects.ReadProperty(s.object, s.property, s.arrayIndex)
+	if err != nil {
+		return nil, err
+	}
+	values := []bactype.Property{{Type: s.property, ArrayIndex: s.arrayIndex, Data: value}}
+	if s.property == property.StatusFlags {
+		return values, nil
+	}
+	flags, err := objects.ReadProperty(s.object, property.StatusFlags, bactype.ArrayAll)
+	if err == nil {
+		values = append(values, bactype.Property{
+			Type:       property.StatusFlags,
+			ArrayIndex: bactype.ArrayAll,
+			Data:       flags,
+		})
+	}
+	return values, nil
+}
+
+// covIncrement returns the minimum change of an analog value before a
+// notification is sent.
+func (s *covSubscriber) covIncrement(objects *store.Store) float32 {
+	if s.increment != nil {
+		return *s.increment
+	}
+	if s.property != property.PresentValue {
+		return 0
+	}
+	increment, _ := objects.ReadProperty(s.object, property.COVIncrement, bactype.ArrayAll)
+	f, _ := increment.(float32)
+	return f
+}
+
+// changed returns if the values differ from the last notification. Analog
+// values have to change by at least the increment, any other change of value
+// or of the status flags is always notified.
+func (s *covSubscriber) changed(values []bactype.Property, increment float32) bool {
+	if len(values) != len(s.last) {
+		return true
+	}
+	for i, v := range values {
+		last := s.last[i].Data
+		f, ok := v.Data.(float32)
+		lastF, lastOK := last.(float32)
+		if ok && lastOK {
+			if f != lastF && math.Abs(float64(f-lastF)) >= float64(increment) {
+				return true
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v.Data, last) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeRemaining returns the seconds left in the subscription, which is 0 for
+// subscriptions that never expire.
+func (s *covSubscriber) timeRemaining(now time.Time) uint32 {
+	if s.expires.IsZero() || !now.Before(s.expires) {
+		return 0
+	}
+	return uint32(s.expires.Sub(now) / time.Second)
+}
+
+// covNotice is a notification that is due to be sent to a subscriber
+type covNotice struct {
+	addr      bactype.Address
+	confirmed bool
+	data      bactype.COVNotification
+}
+
+func (s *covSubscriber) notice(device bactype.ObjectID, values []bactype.Property, now time.Time) covNotice {
+	return covNotice{
+		addr:      s.addr,
+		confirmed: s.confirmed,
+		data: bactype.COVNotification{
+			ProcessID:     s.processID,
+			Device:        device,
+			Object:        s.object,
+			TimeRemaining: s.timeRemaining(now),
+			Values:        values,
+		},
+	}
+}
+
+// covSubscribers holds the subscriptions other devices hold on our objects.
+// Expired subscriptions are removed whenever the subscriptions are used.
+type covSubscribers struct {
+	mutex sync.Mutex
+	subs  []*covSubscriber
+}
+
+// subscribe adds the subscription, replacing the previous subscription of the
+// same subscriber which renews its lifetime.
+func (m *covSubscribers) subscribe(sub *covSubscriber, now time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.expire(now)
+	for i, s := range m.subs {
+		if s.same(sub) {
+			m.subs[i] = sub
+			return nil
+		}
+	}
+	if len(m.subs) >= maxCOVSubscribers {
+		return &bactype.PropertyError{
+			Class: bactype.ErrorClassResources,
+			Code:  bactype.ErrorCodeNoSpaceToAddListElement,
+		}
+	}
+	m.subs = append(m.subs, sub)
+	return nil
+}
+
+// cancel removes the subscription of the same subscriber, if there is one.
+func (m *covSubscribers) cancel(sub *covSubscriber) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i, s := range m.subs {
+		if s.same(sub) {
+			m.subs = append(m.subs[:i], m.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// expire removes the subscriptions whose lifetime has passed. The lock must be
+// held.
+func (m *covSubscribers) expire(now time.Time) {
+	subs := m.subs[:0]
+	for _, s := range m.subs {
+		if s.expires.IsZero() || now.Before(s.expires) {
+			subs = append(subs, s)
+		}
+	}
+	for i := len(subs); i < len(m.subs); i++ {
+		m.subs[i] = nil
+	}
+	m.subs = subs
+}
+
+// changed returns the notifications of the subscriptions that monitor the
+// property that changed and whose values changed enough since their last
+// notification.
+func (m *covSubscribers) changed(device bactype.ObjectID, id bactype.ObjectID, prop uint32, objects *store.Store, now time.Time) []covNotice {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.expire(now)
+
+	var notices []covNotice
+	for _, s := range m.subs {
+		if s.object != id || (prop != s.property && prop != property.StatusFlags) {
+			continue
+		}
+		values, err := s.values(objects)
+		if err != nil || !s.changed(values, s.covIncrement(objects)) {
+			continue
+		}
+		s.last = values
+		notices = append(notices, s.notice(device, values, now))
+	}
+	return notices
+}
+
+// objectChanged notifies the subscribers of an object whose monitored values
+// changed.
+func (c *Client) objectChanged(id bactype.ObjectID, prop uint32, _ interface{}) {
+	for _, n := range c.subscribers.changed(c.device.ID, id, prop, c.objects, time.Now()) {
+		go c.notifyCOV(n)
+	}
+}
+
+// notifyCOV sends a notification to a subscriber. Confirmed notifications are
+// sent again until the subscriber acknowledges them or we run out of tries.
+func (c *Client) notifyCOV(n covNotice) {
+	var err error
+	if n.confirmed {
+		err = c.simpleAckRequest(bactype.Device{Addr: n.addr}, func(enc *encoding.Encoder, id uint8) error {
+			return enc.ConfirmedCOVNotification(id, n.data)
+		})
+	} else {
+		err = c.sendUnconfirmed(n.addr, func(enc *encoding.Encoder) error {
+			return enc.UnconfirmedCOVNotification(n.data)
+		})
+	}
+	if err != nil {
+		c.log.Errorf("unable to send cov notification to subscriber %d: %v", n.data.ProcessID, err)
+	}
+}
+
+// handleSubscribeCOV subscribes another device to the present value and status
+// flags of one of our objects.
+func (c *Client) handleSubscribeCOV(src bactype.Address, apdu bactype.APDU) error {
+	if c.objects == nil {
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService)
+	}
+	var req bactype.SubscribeCOV
+	if err := encoding.NewDecoder(apdu.RawData).SubscribeCOV(&req); err != nil {
+		c.log.Debugf("unable to decode subscribe cov request: %v", err)
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonInvalidTag)
+	}
+
+	sub := &covSubscriber{
+		addr:       src,
+		processID:  req.ProcessID,
+		object:     c.localObject(req.Object),
+		property:   property.PresentValue,
+		arrayIndex: bactype.ArrayAll,
+	}
+	if sub.object.Type == bactype.DeviceType && !req.Cancel {
+		return c.errorReply(src, apdu, &bactype.PropertyError{
+			Class: bactype.ErrorClassObject,
+			Code:  bactype.ErrorCodeOptionalFunctionalityNotSupported,
+		})
+	}
+	return c.subscribe(src, apdu, sub, req)
+}
+
+// handleSubscribeCOVProperty subscribes another device to a single property of
+// one of our objects.
+func (c *Client) handleSubscribeCOVProperty(src bactype.Address, apdu bactype.APDU) error {
+	if c.objects == nil {
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService)
+	}
+	var req bactype.SubscribeCOVProperty
+	if err := encoding.NewDecoder(apdu.RawData).SubscribeCOVProperty(&req); err != nil {
+		c.log.Debugf("unable to decode subscribe cov property request: %v", err)
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonInvalidTag)
+	}
+
+	sub := &covSubscriber{
+		addr:       src,
+		processID:  req.ProcessID,
+		object:     c.localObject(req.Object),
+		property:   req.Property,
+		arrayIndex: req.ArrayIndex,
+		increment:  req.COVIncrement,
+	}
+	return c.subscribe(src, apdu, sub, req.SubscribeCOV)
+}
+
+// subscribe adds or cancels a subscription and acknowledges the request. New
+// and renewed subscriptions are sent the current values right away.
+func (c *Client) subscribe(src bactype.Address, apdu bactype.APDU, sub *covSubscriber, req bactype.SubscribeCOV) error {
+	if req.Cancel {
+		c.subscribers.cancel(sub)
+		return c.simpleAck(src, apdu.InvokeId, apdu.Service)
+	}
+
+	now := time.Now()
+	sub.confirmed = req.IssueConfirmed
+	if req.Lifetime > 0 {
+		sub.expires = now.Add(time.Duration(req.Lifetime) * time.Second)
+	}
+	values, err := sub.values(c.objects)
+	if err != nil {
+		return c.errorReply(src, apdu, err)
+	}
+	sub.last = values
+	if err = c.subscribers.subscribe(sub, now); err != nil {
+		return c.errorReply(src, apdu, err)
+	}
+
+	if err = c.simpleAck(src, apdu.InvokeId, apdu.Service); err != nil {
+		return err
+	}
+	c.notifyCOV(sub.notice(c.device.ID, values, now))
+	return nil
+}
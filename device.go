@@ -37,6 +37,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/alexbeltran/gobacnet/store"
 	"github.com/alexbeltran/gobacnet/tsm"
 	bactype "github.com/alexbeltran/gobacnet/types"
 	"github.com/alexbeltran/gobacnet/utsm"
@@ -54,6 +55,60 @@ type Client struct {
 	utsm             *utsm.Manager
 	listener         *net.UDPConn
 	log              *logrus.Logger
+	cov              subscriptionManager[bactype.COVNotification]
+	events           subscriptionManager[bactype.EventNotification]
+	private          privateTransferManager
+	textMessages     textMessageHandler
+	segmentations    segmentationCache
+
+	// device is the identity we answer who is requests with. We only act as a
+	// device once an instance has been given with DeviceInstance, in which
+	// case objects holds the objects we serve.
+	device      bactype.Device
+	objects     *store.Store
+	subscribers covSubscribers
+}
+
+// ClientOption are functions passed to NewClient to configure the client
+type ClientOption func(c *Client)
+
+// DeviceInstance lets the client act as a BACnet device with the given
+// instance, answering who is requests so other devices can find it.
+func DeviceInstance(instance bactype.ObjectInstance) ClientOption {
+	return func(c *Client) {
+		c.device.ID = bactype.ObjectID{Type: bactype.DeviceType, Instance: instance}
+	}
+}
+
+// DeviceVendor sets the vendor id announced by the local device.
+func DeviceVendor(vendor uint32) ClientOption {
+	return func(c *Client) {
+		c.device.Vendor = vendor
+	}
+}
+
+// DeviceMaxApdu sets the largest apdu the local device accepts. It defaults to
+// the largest apdu that fits into a BACnet/IP packet.
+func DeviceMaxApdu(max uint32) ClientOption {
+	return func(c *Client) {
+		c.device.MaxApdu = max
+	}
+}
+
+// DeviceSegmentation sets the segmentation announced by the local device. Only
+// NoSegmentation, the default, and SegmentationTransmit are accepted since
+// segmented requests sent to the device are aborted. SegmentationTransmit
+// announces that we send segmented requests. NewClient fails for any other
+// value.
+func DeviceSegmentation(segmentation bactype.Enumerated) ClientOption {
+	return func(c *Client) {
+		c.device.Segmentation = segmentation
+	}
+}
+
+// isDevice returns if the client has been given a device identity
+func (c *Client) isDevice() bool {
+	return c.device.ID.Type == bactype.DeviceType
 }
 
 // getBroadcast uses the given address with subnet to return the broadcast address
@@ -70,9 +125,28 @@ func getBroadcast(addr string) (net.IP, error) {
 }
 
 // NewClient creates a new client with the given interface and
-// port.
-func NewClient(inter string, port int) (*Client, error) {
-	c := &Client{}
+// port. Pass DeviceInstance as an option to have the client act as a device.
+func NewClient(inter string, port int, options ...ClientOption) (*Client, error) {
+	c := &Client{
+		// Replies are not segmented and segmented requests are aborted, so
+		// the local device does not announce segmentation by default.
+		device: bactype.Device{
+			MaxApdu:      bactype.MaxAPDUOverIP,
+			Segmentation: bactype.NoSegmentation,
+		},
+	}
+	for _, op := range options {
+		op(c)
+	}
+	switch c.device.Segmentation {
+	case bactype.NoSegmentation, bactype.SegmentationTransmit:
+	default:
+		return nil, fmt.Errorf("device segmentation %d is not supported, use NoSegmentation or SegmentationTransmit", c.device.Segmentation)
+	}
+	if c.isDevice() {
+		c.objects = store.New(c.device)
+		c.objects.Watch(c.objectChanged)
+	}
 	i, err := net.InterfaceByName(inter)
 	if err != nil {
 		return c, err
@@ -116,11 +190,11 @@ func NewClient(inter string, port int) (*Client, error) {
 	c.broadcastAddress = broadcast
 
 	c.tsm = tsm.New(defaultStateSize)
-	options := []utsm.ManagerOption{
+	managerOptions := []utsm.ManagerOption{
 		utsm.DefaultSubscriberTimeout(time.Second * time.Duration(10)),
 		utsm.DefaultSubscriberLastReceivedTimeout(time.Second * time.Duration(2)),
 	}
-	c.utsm = utsm.NewManager(options...)
+	c.utsm = utsm.NewManager(managerOptions...)
 	udp, _ := net.ResolveUDPAddr("udp4", fmt.Sprintf(":%d", c.port))
 	conn, err := net.ListenUDP("udp", udp)
 	if err != nil {
@@ -0,0 +1,520 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// GetEventInformation is a service request for the summaries of the objects
+// of a device that have active events or unacknowledged transitions.
+func (e *Encoder) GetEventInformation(invokeID uint8, data bactype.GetEventInformation) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedGetEventInformation,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 (OPTIONAL) - Last Received Object ID
+	if data.LastObject != nil {
+		e.contextObjectID(0, data.LastObject.Type, data.LastObject.Instance)
+	}
+	return e.Error()
+}
+
+// GetEventInformation decodes the service data of a get event information
+// request.
+func (d *Decoder) GetEventInformation(data *bactype.GetEventInformation) error {
+	data.LastObject = nil
+	if d.isContextTag(0) {
+		id, err := d.contextObjectID(0)
+		if err != nil {
+			return err
+		}
+		data.LastObject = &id
+	}
+	return d.Error()
+}
+
+// GetEventInformationAck is the response made to a get event information
+// request.
+func (e *Encoder) GetEventInformationAck(invokeID uint8, data bactype.GetEventInformationAck) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedGetEventInformation,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+
+	// Tag 0 - List of Event Summaries
+	e.openingTag(0)
+	for _, s := range data.Summaries {
+		e.contextObjectID(0, s.Object.Type, s.Object.Instance)
+		e.contextEnumerated(1, uint32(s.EventState))
+		e.contextBitString(2, s.AcknowledgedTransitions)
+		e.openingTag(3)
+		for _, ts := range s.EventTimeStamps {
+			e.timeStampChoice(ts)
+		}
+		e.closingTag(3)
+		e.contextEnumerated(4, uint32(s.NotifyType))
+		e.contextBitString(5, s.EventEnable)
+		e.openingTag(6)
+		for _, p := range s.EventPriorities {
+			e.AppData(p)
+		}
+		e.closingTag(6)
+	}
+	e.closingTag(0)
+
+	// Tag 1 - More Events
+	e.contextBoolean(1, data.MoreEvents)
+	return e.Error()
+}
+
+// GetEventInformationAck decodes a page of event summaries
+func (d *Decoder) GetEventInformationAck(data *bactype.GetEventInformationAck) error {
+	// Tag 0 - List of Event Summaries
+	if err := d.openingTag(0); err != nil {
+		return err
+	}
+	data.Summaries = nil
+	for !d.isClosingTag(0) {
+		if d.len() == 0 {
+			return fmt.Errorf("missing end of event summaries")
+		}
+		s, err := d.eventSummary()
+		if err != nil {
+			return err
+		}
+		data.Summaries = append(data.Summaries, s)
+	}
+	if err := d.closingTag(0); err != nil {
+		return err
+	}
+
+	// Tag 1 - More Events
+	var err error
+	data.MoreEvents, err = d.contextBoolean(1)
+	return err
+}
+
+func (d *Decoder) eventSummary() (bactype.EventSummary, error) {
+	var s bactype.EventSummary
+	var err error
+
+	if s.Object, err = d.contextObjectID(0); err != nil {
+		return s, err
+	}
+	state, err := d.contextEnumerated(1)
+	if err != nil {
+		return s, err
+	}
+	s.EventState = bactype.EventState(state)
+	if s.AcknowledgedTransitions, err = d.contextBitString(2); err != nil {
+		return s, err
+	}
+
+	if err = d.openingTag(3); err != nil {
+		return s, err
+	}
+	for i := range s.EventTimeStamps {
+		if s.EventTimeStamps[i], err = d.timeStampChoice(); err != nil {
+			return s, err
+		}
+	}
+	if err = d.closingTag(3); err != nil {
+		return s, err
+	}
+
+	notifyType, err := d.contextEnumerated(4)
+	if err != nil {
+		return s, err
+	}
+	s.NotifyType = bactype.NotifyType(notifyType)
+	if s.EventEnable, err = d.contextBitString(5); err != nil {
+		return s, err
+	}
+
+	if err = d.openingTag(6); err != nil {
+		return s, err
+	}
+	for i := range s.EventPriorities {
+		if s.EventPriorities[i], err = d.appUnsigned(); err != nil {
+			return s, err
+		}
+	}
+	return s, d.closingTag(6)
+}
+
+// AcknowledgeAlarm is a service request to acknowledge an event transition of
+// an object.
+func (e *Encoder) AcknowledgeAlarm(invokeID uint8, data bactype.AcknowledgeAlarm) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedAcknowledgeAlarm,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 - Acknowledging Process ID
+	e.contextUnsigned(0, data.ProcessID)
+
+	// Tag 1 - Event Object ID
+	e.contextObjectID(1, data.Object.Type, data.Object.Instance)
+
+	// Tag 2 - Event State Acknowledged
+	e.contextEnumerated(2, uint32(data.EventState))
+
+	// Tag 3 - Time Stamp
+	e.timeStamp(3, data.TimeStamp)
+
+	// Tag 4 - Acknowledgment Source
+	e.contextString(4, data.Source)
+
+	// Tag 5 - Time of Acknowledgment
+	e.timeStamp(5, data.AckTime)
+	return e.Error()
+}
+
+// AcknowledgeAlarm decodes the service data of an acknowledge alarm request.
+func (d *Decoder) AcknowledgeAlarm(data *bactype.AcknowledgeAlarm) error {
+	var err error
+
+	// Tag 0 - Acknowledging Process ID
+	if data.ProcessID, err = d.contextUnsigned(0); err != nil {
+		return err
+	}
+
+	// Tag 1 - Event Object ID
+	if data.Object, err = d.contextObjectID(1); err != nil {
+		return err
+	}
+
+	// Tag 2 - Event State Acknowledged
+	state, err := d.contextEnumerated(2)
+	if err != nil {
+		return err
+	}
+	data.EventState = bactype.EventState(state)
+
+	// Tag 3 - Time Stamp
+	if data.TimeStamp, err = d.timeStamp(3); err != nil {
+		return err
+	}
+
+	// Tag 4 - Acknowledgment Source
+	if data.Source, err = d.contextString(4); err != nil {
+		return err
+	}
+
+	// Tag 5 - Time of Acknowledgment
+	data.AckTime, err = d.timeStamp(5)
+	return err
+}
+
+// GetAlarmSummary is a service request for the objects of a device that are
+// in an alarm state.
+func (e *Encoder) GetAlarmSummary(invokeID uint8) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedGetAlarmSummary,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	return e.Error()
+}
+
+// GetAlarmSummaryAck is the response made to a get alarm summary request.
+func (e *Encoder) GetAlarmSummaryAck(invokeID uint8, data []bactype.AlarmSummary) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedGetAlarmSummary,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+
+	for _, s := range data {
+		e.AppData(s.Object)
+		e.AppData(bactype.Enumerated(s.AlarmState))
+		e.AppData(s.AcknowledgedTransitions)
+	}
+	return e.Error()
+}
+
+// GetAlarmSummaryAck decodes the alarm summaries that make up the rest of the
+// message.
+func (d *Decoder) GetAlarmSummaryAck(data *[]bactype.AlarmSummary) error {
+	var out []bactype.AlarmSummary
+	for d.len() > 0 {
+		var s bactype.AlarmSummary
+		var err error
+		if s.Object, err = d.appObjectID(); err != nil {
+			return err
+		}
+		state, err := d.appUnsigned()
+		if err != nil {
+			return err
+		}
+		s.AlarmState = bactype.EventState(state)
+		if s.AcknowledgedTransitions, err = d.appBitString(); err != nil {
+			return err
+		}
+		out = append(out, s)
+	}
+	*data = out
+	return d.Error()
+}
+
+// GetEnrollmentSummary is a service request for the event enrollments of a
+// device that match the filters.
+func (e *Encoder) GetEnrollmentSummary(invokeID uint8, data bactype.GetEnrollmentSummary) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedGetEnrollmentSummary,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 - Acknowledgment Filter
+	e.contextEnumerated(0, uint32(data.AckFilter))
+
+	// Tag 1 (OPTIONAL) - Enrollment Filter
+	if data.Recipient != nil {
+		e.openingTag(1)
+		e.openingTag(0)
+		if err := e.recipient(*data.Recipient); err != nil {
+			return err
+		}
+		e.closingTag(0)
+		e.contextUnsigned(1, data.ProcessID)
+		e.closingTag(1)
+	}
+
+	// Tag 2 (OPTIONAL) - Event State Filter
+	if data.EventState != nil {
+		e.contextEnumerated(2, uint32(*data.EventState))
+	}
+
+	// Tag 3 (OPTIONAL) - Event Type Filter
+	if data.EventType != nil {
+		e.contextEnumerated(3, uint32(*data.EventType))
+	}
+
+	// Tag 4 (OPTIONAL) - Priority Filter
+	if data.Priority != nil {
+		e.openingTag(4)
+		e.contextUnsigned(0, uint32(data.Priority.Min))
+		e.contextUnsigned(1, uint32(data.Priority.Max))
+		e.closingTag(4)
+	}
+
+	// Tag 5 (OPTIONAL) - Notification Class Filter
+	if data.NotificationClass != nil {
+		e.contextUnsigned(5, *data.NotificationClass)
+	}
+	return e.Error()
+}
+
+// GetEnrollmentSummary decodes the service data of a get enrollment summary
+// request.
+func (d *Decoder) GetEnrollmentSummary(data *bactype.GetEnrollmentSummary) error {
+	// Tag 0 - Acknowledgment Filter
+	ackFilter, err := d.contextEnumerated(0)
+	if err != nil {
+		return err
+	}
+	data.AckFilter = bactype.AckFilter(ackFilter)
+
+	// Tag 1 (OPTIONAL) - Enrollment Filter
+	data.Recipient = nil
+	data.ProcessID = 0
+	if d.isOpeningTag(1) {
+		if err = d.openingTag(1); err != nil {
+			return err
+		}
+		if err = d.openingTag(0); err != nil {
+			return err
+		}
+		r, err := d.recipient()
+		if err != nil {
+			return err
+		}
+		data.Recipient = &r
+		if err = d.closingTag(0); err != nil {
+			return err
+		}
+		if data.ProcessID, err = d.contextUnsigned(1); err != nil {
+			return err
+		}
+		if err = d.closingTag(1); err != nil {
+			return err
+		}
+	}
+
+	// Tag 2 (OPTIONAL) - Event State Filter
+	data.EventState = nil
+	if d.isContextTag(2) {
+		v, err := d.contextEnumerated(2)
+		if err != nil {
+			return err
+		}
+		state := bactype.EventStateFilter(v)
+		data.EventState = &state
+	}
+
+	// Tag 3 (OPTIONAL) - Event Type Filter
+	data.EventType = nil
+	if d.isContextTag(3) {
+		v, err := d.contextEnumerated(3)
+		if err != nil {
+			return err
+		}
+		eventType := bactype.EventType(v)
+		data.EventType = &eventType
+	}
+
+	// Tag 4 (OPTIONAL) - Priority Filter
+	data.Priority = nil
+	if d.isOpeningTag(4) {
+		if err = d.openingTag(4); err != nil {
+			return err
+		}
+		min, err := d.contextUnsigned(0)
+		if err != nil {
+			return err
+		}
+		max, err := d.contextUnsigned(1)
+		if err != nil {
+			return err
+		}
+		data.Priority = &bactype.PriorityFilter{Min: uint8(min), Max: uint8(max)}
+		if err = d.closingTag(4); err != nil {
+			return err
+		}
+	}
+
+	// Tag 5 (OPTIONAL) - Notification Class Filter
+	data.NotificationClass = nil
+	if d.isContextTag(5) {
+		class, err := d.contextUnsigned(5)
+		if err != nil {
+			return err
+		}
+		data.NotificationClass = &class
+	}
+	return d.Error()
+}
+
+// GetEnrollmentSummaryAck is the response made to a get enrollment summary
+// request.
+func (e *Encoder) GetEnrollmentSummaryAck(invokeID uint8, data []bactype.EnrollmentSummary) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedGetEnrollmentSummary,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+
+	for _, s := range data {
+		e.AppData(s.Object)
+		e.AppData(bactype.Enumerated(s.EventType))
+		e.AppData(bactype.Enumerated(s.EventState))
+		e.AppData(uint32(s.Priority))
+		if s.NotificationClass != nil {
+			e.AppData(*s.NotificationClass)
+		}
+	}
+	return e.Error()
+}
+
+// GetEnrollmentSummaryAck decodes the enrollment summaries that make up the
+// rest of the message.
+func (d *Decoder) GetEnrollmentSummaryAck(data *[]bactype.EnrollmentSummary) error {
+	var out []bactype.EnrollmentSummary
+	for d.len() > 0 {
+		var s bactype.EnrollmentSummary
+		var err error
+		if s.Object, err = d.appObjectID(); err != nil {
+			return err
+		}
+		eventType, err := d.appUnsigned()
+		if err != nil {
+			return err
+		}
+		s.EventType = bactype.EventType(eventType)
+		state, err := d.appUnsigned()
+		if err != nil {
+			return err
+		}
+		s.EventState = bactype.EventState(state)
+		priority, err := d.appUnsigned()
+		if err != nil {
+			return err
+		}
+		s.Priority = uint8(priority)
+
+		// The notification class is optional, the next summary starts with
+		// an object identifier.
+		if tag, meta := d.peekTag(); d.len() > 0 && tag == tagUint && !meta.isContextSpecific() {
+			class, err := d.appUnsigned()
+			if err != nil {
+				return err
+			}
+			s.NotificationClass = &class
+		}
+		out = append(out, s)
+	}
+	*data = out
+	return d.Error()
+}
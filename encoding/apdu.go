@@ -38,28 +38,41 @@ import (
 )
 
 func (e *Encoder) APDU(a bactype.APDU) error {
+	if a.DataType == bactype.ConfirmedServiceRequest && e.maxSegs > 0 {
+		a.SegmentedResponseAccepted = true
+		a.MaxSegs = e.maxSegs
+	}
+
 	meta := APDUMetadata(0)
 	meta.setDataType(a.DataType)
-	meta.setMoreFollows(a.MoreFollows)
-	meta.setSegmentedMessage(a.SegmentedMessage)
-	meta.setSegmentedAccepted(a.SegmentedResponseAccepted)
+	switch a.DataType {
+	case bactype.SegmentAck:
+		meta.setInfoMask(a.NegativeAck, apduMaskNegativeAck)
+		meta.setInfoMask(a.Server, apduMaskServer)
+	case bactype.Abort:
+		meta.setInfoMask(a.Server, apduMaskServer)
+	default:
+		meta.setMoreFollows(a.MoreFollows)
+		meta.setSegmentedMessage(a.SegmentedMessage)
+		meta.setSegmentedAccepted(a.SegmentedResponseAccepted)
+	}
 	e.write(meta)
 
 	switch a.DataType {
 	case bactype.ComplexAck:
 		e.apduComplexAck(a)
+	case bactype.SimpleAck:
+		e.apduSimpleAck(a)
 	case bactype.UnconfirmedServiceRequest:
 		e.apduUnconfirmed(a)
 	case bactype.ConfirmedServiceRequest:
 		e.apduConfirmed(a)
 	case bactype.SegmentAck:
-		return fmt.Errorf("Decoded Segmented")
+		e.apduSegmentAck(a)
 	case bactype.Error:
-		return fmt.Errorf("Decoded Error")
-	case bactype.Reject:
-		return fmt.Errorf("Decoded Rejected")
-	case bactype.Abort:
-		return fmt.Errorf("Decoded Aborted")
+		e.apduError(a)
+	case bactype.Reject, bactype.Abort:
+		e.apduReject(a)
 	default:
 		return fmt.Errorf("Unknown PDU type:%d", meta.DataType())
 	}
@@ -81,6 +94,39 @@ func (e *Encoder) apduUnconfirmed(a bactype.APDU) {
 }
 
 func (e *Encoder) apduComplexAck(a bactype.APDU) {
+	e.write(a.InvokeId)
+	if a.SegmentedMessage {
+		e.write(a.Sequence)
+		e.write(a.WindowNumber)
+	}
+	e.write(a.Service)
+}
+
+// apduSegmentAck acknowledges the segments up to Sequence. WindowNumber is the
+// number of segments that may be sent before the next ack.
+func (e *Encoder) apduSegmentAck(a bactype.APDU) {
+	e.write(a.InvokeId)
+	e.write(a.Sequence)
+	e.write(a.WindowNumber)
+}
+
+// apduError encodes the error class and code of a failed request. Errors that
+// carry additional information are not supported.
+func (e *Encoder) apduError(a bactype.APDU) {
+	e.write(a.InvokeId)
+	e.write(a.Service)
+	e.AppData(bactype.Enumerated(a.Error.Class))
+	e.AppData(bactype.Enumerated(a.Error.Code))
+}
+
+// apduReject is used by both rejects and aborts which only differ by their
+// type.
+func (e *Encoder) apduReject(a bactype.APDU) {
+	e.write(a.InvokeId)
+	e.write(a.Reason)
+}
+
+func (e *Encoder) apduSimpleAck(a bactype.APDU) {
 	e.write(a.InvokeId)
 	e.write(a.Service)
 }
@@ -88,26 +134,34 @@ func (e *Encoder) apduComplexAck(a bactype.APDU) {
 func (d *Decoder) APDU(a *bactype.APDU) error {
 	var meta APDUMetadata
 	d.decode(&meta)
-	a.SegmentedMessage = meta.isSegmentedMessage()
-	a.SegmentedResponseAccepted = meta.segmentedResponseAccepted()
-	a.MoreFollows = meta.moreFollows()
 	a.DataType = meta.DataType()
+	switch a.DataType {
+	case bactype.SegmentAck:
+		a.NegativeAck = meta.checkMask(apduMaskNegativeAck)
+		a.Server = meta.checkMask(apduMaskServer)
+	case bactype.Abort:
+		a.Server = meta.checkMask(apduMaskServer)
+	default:
+		a.SegmentedMessage = meta.isSegmentedMessage()
+		a.SegmentedResponseAccepted = meta.segmentedResponseAccepted()
+		a.MoreFollows = meta.moreFollows()
+	}
 
 	switch a.DataType {
 	case bactype.ComplexAck:
 		return d.apduComplexAck(a)
+	case bactype.SimpleAck:
+		return d.apduSimpleAck(a)
 	case bactype.UnconfirmedServiceRequest:
 		return d.apduUnconfirmed(a)
 	case bactype.ConfirmedServiceRequest:
 		return d.apduConfirmed(a)
 	case bactype.SegmentAck:
-		return fmt.Errorf("Segmented")
+		return d.apduSegmentAck(a)
 	case bactype.Error:
 		return d.apduError(a)
-	case bactype.Reject:
-		return fmt.Errorf("Rejected")
-	case bactype.Abort:
-		return fmt.Errorf("Aborted")
+	case bactype.Reject, bactype.Abort:
+		return d.apduReject(a)
 	default:
 		return fmt.Errorf("Unknown PDU type:%d", a.DataType)
 	}
@@ -116,6 +170,14 @@ func (d *Decoder) APDU(a *bactype.APDU) error {
 func (d *Decoder) apduError(a *bactype.APDU) error {
 	d.decode(&a.InvokeId)
 	d.decode(&a.Service)
+
+	// Some services wrap the error class and code within a constructed error
+	// type that is followed by additional information about the error.
+	constructed := d.isOpeningTag(0)
+	if constructed {
+		d.tagNumber()
+	}
+
 	class, err := d.AppData()
 	if err != nil {
 		return err
@@ -125,7 +187,7 @@ func (d *Decoder) apduError(a *bactype.APDU) error {
 	if !ok {
 		return fmt.Errorf("Unable to decode error class")
 	}
-	a.Error.Class = c
+	a.Error.Class = bactype.ErrorClass(c)
 
 	code, err := d.AppData()
 	if err != nil {
@@ -136,12 +198,46 @@ func (d *Decoder) apduError(a *bactype.APDU) error {
 	if !ok {
 		return fmt.Errorf("Unable to decode error code")
 	}
-	a.Error.Code = c
+	a.Error.Code = bactype.ErrorCode(c)
 
-	return nil
+	if constructed {
+		if err = d.closingTag(0); err != nil {
+			return err
+		}
+	}
+
+	// Keep any service specific error information
+	if d.len() > 0 {
+		a.RawData = make([]byte, d.len())
+		d.decode(a.RawData)
+	}
+	return d.Error()
 }
 
 func (d *Decoder) apduComplexAck(a *bactype.APDU) error {
+	d.decode(&a.InvokeId)
+	if a.SegmentedMessage {
+		d.decode(&a.Sequence)
+		d.decode(&a.WindowNumber)
+	}
+	d.decode(&a.Service)
+	return d.Error()
+}
+
+func (d *Decoder) apduSegmentAck(a *bactype.APDU) error {
+	d.decode(&a.InvokeId)
+	d.decode(&a.Sequence)
+	d.decode(&a.WindowNumber)
+	return d.Error()
+}
+
+func (d *Decoder) apduReject(a *bactype.APDU) error {
+	d.decode(&a.InvokeId)
+	d.decode(&a.Reason)
+	return d.Error()
+}
+
+func (d *Decoder) apduSimpleAck(a *bactype.APDU) error {
 	d.decode(&a.InvokeId)
 	d.decode(&a.Service)
 	return d.Error()
@@ -178,6 +274,10 @@ const (
 	apduMaskMoreFollows       = 1 << 2
 	apduMaskSegmentedAccepted = 1 << 1
 	// Bit 0 is reserved
+
+	// Segment acks use the lower bits for their own flags
+	apduMaskNegativeAck = 1 << 1
+	apduMaskServer      = 1 << 0
 )
 
 func (meta *APDUMetadata) setInfoMask(b bool, mask byte) {
@@ -150,8 +150,12 @@ func (e *Encoder) date(dt bactype.Date) {
 
 func (d *Decoder) date(dt *bactype.Date) {
 	var year, month, day, dayOfWeek uint8
+	d.decode(&year)
+	d.decode(&month)
+	d.decode(&day)
+	d.decode(&dayOfWeek)
 
-	if dt.Year != bactype.UnspecifiedTime {
+	if year != bactype.UnspecifiedTime {
 		dt.Year = int(year) + epochYear
 	} else {
 		dt.Year = int(year)
@@ -185,6 +189,73 @@ func (d *Decoder) time(t *bactype.Time) {
 
 }
 
+// dateTime encodes a BACnetDateTime, an application tagged date followed by an
+// application tagged time.
+func (e *Encoder) dateTime(dt bactype.DateTime) {
+	e.AppData(dt.Date)
+	e.AppData(dt.Time)
+}
+
+func (d *Decoder) dateTime(dt *bactype.DateTime) error {
+	date, err := d.AppData()
+	if err != nil {
+		return err
+	}
+	t, err := d.AppData()
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	if dt.Date, ok = date.(bactype.Date); !ok {
+		return fmt.Errorf("expected a date but got %T", date)
+	}
+	if dt.Time, ok = t.(bactype.Time); !ok {
+		return fmt.Errorf("expected a time but got %T", t)
+	}
+	return nil
+}
+
+// bitstring encodes the bits most significant bit first. The first octet holds
+// the number of unused bits in the last octet.
+func (e *Encoder) bitstring(b bactype.BitString) {
+	unused := (8 - len(b)%8) % 8
+	e.write(uint8(unused))
+
+	octets := make([]byte, (len(b)+7)/8)
+	for i, bit := range b {
+		if bit {
+			octets[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	e.write(octets)
+}
+
+func (d *Decoder) bitstring(b *bactype.BitString, len int) {
+	if len == 0 {
+		*b = bactype.BitString{}
+		return
+	}
+	var unused uint8
+	d.decode(&unused)
+	octets := make([]byte, len-1)
+	d.decode(octets)
+
+	size := 8*(len-1) - int(unused)
+	if size < 0 {
+		size = 0
+	}
+	*b = make(bactype.BitString, size)
+	for i := range *b {
+		(*b)[i] = octets[i/8]&(0x80>>uint(i%8)) > 0
+	}
+}
+
+// bitstringLength returns the number of octets needed to encode the bitstring
+func bitstringLength(b bactype.BitString) uint32 {
+	return uint32(1 + (len(b)+7)/8)
+}
+
 func (e *Encoder) boolean(x bool) {
 	// Boolean information is stored into the length field
 	var length uint32
@@ -214,11 +285,13 @@ func (d *Decoder) double(x *float64) {
 
 func (e *Encoder) AppData(i interface{}) error {
 	switch val := i.(type) {
+	case nil:
+		e.tag(tagInfo{ID: tagNull, Context: appLayerContext, Value: 0})
 	case float32:
 		e.tag(tagInfo{ID: tagReal, Context: appLayerContext, Value: realLen})
 		e.real(val)
 	case float64:
-		e.tag(tagInfo{ID: tagDouble, Context: appLayerContext, Value: realLen})
+		e.tag(tagInfo{ID: tagDouble, Context: appLayerContext, Value: doubleLen})
 		e.double(val)
 	case bool:
 		e.boolean(val)
@@ -226,10 +299,17 @@ func (e *Encoder) AppData(i interface{}) error {
 		// Add 1 to length to account for the encoding byte
 		e.tag(tagInfo{ID: tagCharacterString, Context: appLayerContext, Value: uint32(len(val) + 1)})
 		e.string(val)
+	case []byte:
+		e.tag(tagInfo{ID: tagOctetString, Context: appLayerContext, Value: uint32(len(val))})
+		e.octetstring(val)
 	case uint32:
 		length := valueLength(val)
 		e.tag(tagInfo{ID: tagUint, Context: appLayerContext, Value: uint32(length)})
 		e.unsigned(val)
+	case int32:
+		length := signedLength(val)
+		e.tag(tagInfo{ID: tagInt, Context: appLayerContext, Value: uint32(length)})
+		e.signed(val)
 
 	// Enumerated is pretty much a wrapper for a uint32 with an enumerated associated with it.
 	case bactype.Enumerated:
@@ -240,6 +320,15 @@ func (e *Encoder) AppData(i interface{}) error {
 	case bactype.ObjectID:
 		e.tag(tagInfo{ID: tagObjectID, Context: appLayerContext, Value: objectIDLen})
 		e.objectId(val.Type, val.Instance)
+	case bactype.BitString:
+		e.tag(tagInfo{ID: tagBitString, Context: appLayerContext, Value: bitstringLength(val)})
+		e.bitstring(val)
+	case bactype.Date:
+		e.tag(tagInfo{ID: tagDate, Context: appLayerContext, Value: dateLen})
+		e.date(val)
+	case bactype.Time:
+		e.tag(tagInfo{ID: tagTime, Context: appLayerContext, Value: timeLen})
+		e.time(val)
 
 	default:
 		err := fmt.Errorf("Unknown type %T", i)
@@ -256,7 +345,7 @@ func (d *Decoder) AppData() (interface{}, error) {
 
 	switch tag {
 	case tagNull:
-		return nil, fmt.Errorf("Null tag")
+		return nil, d.Error()
 	case tagBool:
 		// Originally this was in C so non 0 values are considered
 		// true
@@ -284,7 +373,9 @@ func (d *Decoder) AppData() (interface{}, error) {
 		err := d.string(&s, len-1)
 		return s, err
 	case tagBitString:
-		return nil, fmt.Errorf("decoding bit strings is currently unsupported")
+		var b bactype.BitString
+		d.bitstring(&b, len)
+		return b, d.Error()
 	case tagEnumerated:
 		return d.enumerated(len), d.Error()
 	case tagDate:
@@ -305,3 +396,68 @@ func (d *Decoder) AppData() (interface{}, error) {
 		return nil, fmt.Errorf("Unsupported tag: %d", tag)
 	}
 }
+
+// appUnsigned decodes application data that must be an unsigned value
+func (d *Decoder) appUnsigned() (uint32, error) {
+	v, err := d.AppData()
+	if err != nil {
+		return 0, err
+	}
+	x, ok := v.(uint32)
+	if !ok {
+		return 0, fmt.Errorf("expected an unsigned value but got %T", v)
+	}
+	return x, nil
+}
+
+// appSigned decodes application data that must be a signed value
+func (d *Decoder) appSigned() (int32, error) {
+	v, err := d.AppData()
+	if err != nil {
+		return 0, err
+	}
+	x, ok := v.(int32)
+	if !ok {
+		return 0, fmt.Errorf("expected a signed value but got %T", v)
+	}
+	return x, nil
+}
+
+// appObjectID decodes application data that must be an object identifier
+func (d *Decoder) appObjectID() (bactype.ObjectID, error) {
+	v, err := d.AppData()
+	if err != nil {
+		return bactype.ObjectID{}, err
+	}
+	x, ok := v.(bactype.ObjectID)
+	if !ok {
+		return bactype.ObjectID{}, fmt.Errorf("expected an object identifier but got %T", v)
+	}
+	return x, nil
+}
+
+// appOctetString decodes application data that must be an octet string
+func (d *Decoder) appOctetString() ([]byte, error) {
+	v, err := d.AppData()
+	if err != nil {
+		return nil, err
+	}
+	x, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected an octet string but got %T", v)
+	}
+	return x, nil
+}
+
+// appBitString decodes application data that must be a bit string
+func (d *Decoder) appBitString() (bactype.BitString, error) {
+	v, err := d.AppData()
+	if err != nil {
+		return nil, err
+	}
+	x, ok := v.(bactype.BitString)
+	if !ok {
+		return nil, fmt.Errorf("expected a bit string but got %T", v)
+	}
+	return x, nil
+}
@@ -197,3 +197,22 @@ func TestStringUnicode(t *testing.T) {
 		t.Fatal("an unknown code was prepending to output")
 	}
 }
+
+func TestAdditionalDataTypes(t *testing.T) {
+	values := []interface{}{nil, int32(-1), int32(300), int32(-70000),
+		int32(-9000000), []byte{1, 2, 3}, float64(-12.5), types.BitString{},
+		types.BitString{true, false, true}, types.BitString{false, false, false,
+			false, false, false, false, true, true}}
+	enc := NewEncoder()
+	for _, v := range values {
+		enc.AppData(v)
+	}
+	if err := enc.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(enc.Bytes())
+	for _, v := range values {
+		t.Run("Encoding", subTestSimpleData(t, dec, v))
+	}
+}
@@ -0,0 +1,277 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// SubscribeCOV is a service request to subscribe to the change of value
+// notifications of an object.
+func (e *Encoder) SubscribeCOV(invokeID uint8, data bactype.SubscribeCOV) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedSubscribeCOV,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	e.subscribeCOV(data)
+	return e.Error()
+}
+
+func (e *Encoder) subscribeCOV(data bactype.SubscribeCOV) {
+	// Tag 0 - Subscriber Process ID
+	e.contextUnsigned(0, data.ProcessID)
+
+	// Tag 1 - Monitored Object ID
+	e.contextObjectID(1, data.Object.Type, data.Object.Instance)
+
+	// A request without the optional values cancels the subscription.
+	if data.Cancel {
+		return
+	}
+
+	// Tag 2 - Issue Confirmed Notifications
+	e.contextBoolean(2, data.IssueConfirmed)
+
+	// Tag 3 - Lifetime
+	e.contextUnsigned(3, data.Lifetime)
+}
+
+// SubscribeCOV decodes the service data of a subscribe cov request.
+func (d *Decoder) SubscribeCOV(data *bactype.SubscribeCOV) error {
+	return d.subscribeCOV(data)
+}
+
+func (d *Decoder) subscribeCOV(data *bactype.SubscribeCOV) error {
+	var err error
+
+	// Tag 0 - Subscriber Process ID
+	data.ProcessID, err = d.contextUnsigned(0)
+	if err != nil {
+		return err
+	}
+
+	// Tag 1 - Monitored Object ID
+	data.Object, err = d.contextObjectID(1)
+	if err != nil {
+		return err
+	}
+
+	data.Cancel = true
+
+	// Tag 2 (OPTIONAL) - Issue Confirmed Notifications
+	if d.isContextTag(2) {
+		data.Cancel = false
+		data.IssueConfirmed, err = d.contextBoolean(2)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tag 3 (OPTIONAL) - Lifetime
+	if d.isContextTag(3) {
+		data.Cancel = false
+		data.Lifetime, err = d.contextUnsigned(3)
+		if err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
+
+// ConfirmedCOVNotification is a confirmed service request that notifies a
+// subscriber of a change of value.
+func (e *Encoder) ConfirmedCOVNotification(invokeID uint8, data bactype.COVNotification) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedCOVNotification,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	return e.covNotification(data)
+}
+
+// UnconfirmedCOVNotification is an unconfirmed service request that notifies
+// a subscriber of a change of value.
+func (e *Encoder) UnconfirmedCOVNotification(data bactype.COVNotification) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedCOVNotification,
+	}
+	e.APDU(a)
+	return e.covNotification(data)
+}
+
+func (e *Encoder) covNotification(data bactype.COVNotification) error {
+	// Tag 0 - Subscriber Process ID
+	e.contextUnsigned(0, data.ProcessID)
+
+	// Tag 1 - Initiating Device ID
+	e.contextObjectID(1, data.Device.Type, data.Device.Instance)
+
+	// Tag 2 - Monitored Object ID
+	e.contextObjectID(2, data.Object.Type, data.Object.Instance)
+
+	// Tag 3 - Time Remaining
+	e.contextUnsigned(3, data.TimeRemaining)
+
+	// Tag 4 - List of Values
+	e.openingTag(4)
+	if err := e.propertyValues(data.Values); err != nil {
+		return err
+	}
+	e.closingTag(4)
+	return e.Error()
+}
+
+// COVNotification decodes the service data of both the confirmed and
+// unconfirmed cov notification.
+func (d *Decoder) COVNotification(data *bactype.COVNotification) error {
+	var err error
+
+	// Tag 0 - Subscriber Process ID
+	data.ProcessID, err = d.contextUnsigned(0)
+	if err != nil {
+		return err
+	}
+
+	// Tag 1 - Initiating Device ID
+	data.Device, err = d.contextObjectID(1)
+	if err != nil {
+		return err
+	}
+
+	// Tag 2 - Monitored Object ID
+	data.Object, err = d.contextObjectID(2)
+	if err != nil {
+		return err
+	}
+
+	// Tag 3 - Time Remaining
+	data.TimeRemaining, err = d.contextUnsigned(3)
+	if err != nil {
+		return err
+	}
+
+	// Tag 4 - List of Values
+	if err = d.openingTag(4); err != nil {
+		return err
+	}
+	data.Values, err = d.propertyValues(4)
+	if err != nil {
+		return err
+	}
+	return d.Error()
+}
+
+// SubscribeCOVProperty is a service request to subscribe to the change of value
+// notifications of a single property of an object.
+func (e *Encoder) SubscribeCOVProperty(invokeID uint8, data bactype.SubscribeCOVProperty) error {
+	if err := isValidPropertyType(data.Property); err != nil {
+		return err
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedSubscribeCOVProperty,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tags 0 to 3 are the same as subscribe cov
+	e.subscribeCOV(data.SubscribeCOV)
+
+	// Tag 4 - Monitored Property
+	e.openingTag(4)
+	e.contextEnumerated(0, data.Property)
+	if data.ArrayIndex != ArrayAll {
+		e.contextUnsigned(1, data.ArrayIndex)
+	}
+	e.closingTag(4)
+
+	// Tag 5 (OPTIONAL) - COV Increment
+	if data.COVIncrement != nil {
+		e.tag(tagInfo{ID: 5, Context: true, Value: realLen})
+		e.real(*data.COVIncrement)
+	}
+	return e.Error()
+}
+
+// SubscribeCOVProperty decodes the service data of a subscribe cov property
+// request.
+func (d *Decoder) SubscribeCOVProperty(data *bactype.SubscribeCOVProperty) error {
+	err := d.subscribeCOV(&data.SubscribeCOV)
+	if err != nil {
+		return err
+	}
+
+	// Tag 4 - Monitored Property
+	if err = d.openingTag(4); err != nil {
+		return err
+	}
+	data.Property, err = d.contextEnumerated(0)
+	if err != nil {
+		return err
+	}
+	data.ArrayIndex = ArrayAll
+	if d.isContextTag(1) {
+		data.ArrayIndex, err = d.contextUnsigned(1)
+		if err != nil {
+			return err
+		}
+	}
+	if err = d.closingTag(4); err != nil {
+		return err
+	}
+
+	// Tag 5 (OPTIONAL) - COV Increment
+	data.COVIncrement = nil
+	if d.isContextTag(5) {
+		if _, err = d.contextTag(5); err != nil {
+			return err
+		}
+		var increment float32
+		d.real(&increment)
+		data.COVIncrement = &increment
+	}
+	return d.Error()
+}
@@ -0,0 +1,176 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// CreateObject is a service request to create an object in a device.
+func (e *Encoder) CreateObject(invokeID uint8, data bactype.CreateObject) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedCreateObject,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 - Object Specifier
+	e.openingTag(0)
+	if data.ID != nil {
+		if err := isValidObjectType(data.ID.Type); err != nil {
+			return err
+		}
+		e.contextObjectID(1, data.ID.Type, data.ID.Instance)
+	} else {
+		if err := isValidObjectType(data.Type); err != nil {
+			return err
+		}
+		e.contextEnumerated(0, uint32(data.Type))
+	}
+	e.closingTag(0)
+
+	// Tag 1 (OPTIONAL) - List of Initial Values
+	if len(data.Properties) > 0 {
+		e.openingTag(1)
+		if err := e.propertyValues(data.Properties); err != nil {
+			return err
+		}
+		e.closingTag(1)
+	}
+	return e.Error()
+}
+
+// CreateObject decodes the service data of a create object request.
+func (d *Decoder) CreateObject(data *bactype.CreateObject) error {
+	// Tag 0 - Object Specifier
+	if err := d.openingTag(0); err != nil {
+		return err
+	}
+	data.ID = nil
+	if d.isContextTag(1) {
+		id, err := d.contextObjectID(1)
+		if err != nil {
+			return err
+		}
+		data.ID = &id
+		data.Type = id.Type
+	} else {
+		t, err := d.contextEnumerated(0)
+		if err != nil {
+			return err
+		}
+		data.Type = bactype.ObjectType(t)
+	}
+	if err := d.closingTag(0); err != nil {
+		return err
+	}
+
+	// Tag 1 (OPTIONAL) - List of Initial Values
+	data.Properties = nil
+	if d.isOpeningTag(1) {
+		if err := d.openingTag(1); err != nil {
+			return err
+		}
+		var err error
+		data.Properties, err = d.propertyValues(1)
+		if err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
+
+// CreateObjectAck is the response made to a create object request. It holds
+// the identifier of the new object.
+func (e *Encoder) CreateObjectAck(invokeID uint8, id bactype.ObjectID) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedCreateObject,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+	e.AppData(id)
+	return e.Error()
+}
+
+// CreateObjectAck decodes the identifier of the created object.
+func (d *Decoder) CreateObjectAck(id *bactype.ObjectID) error {
+	var err error
+	*id, err = d.appObjectID()
+	return err
+}
+
+// CreateObjectError decodes the number of the first initial value that could
+// not be written, which follows the error class and code of a create object
+// error. It is 0 if the error was not caused by an initial value.
+func (d *Decoder) CreateObjectError(firstFailed *uint32) error {
+	var err error
+	*firstFailed, err = d.contextUnsigned(1)
+	return err
+}
+
+// DeleteObject is a service request to delete an object from a device.
+func (e *Encoder) DeleteObject(invokeID uint8, id bactype.ObjectID) error {
+	if err := isValidObjectType(id.Type); err != nil {
+		return err
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedDeleteObject,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	e.AppData(id)
+	return e.Error()
+}
+
+// DeleteObject decodes the service data of a delete object request.
+func (d *Decoder) DeleteObject(id *bactype.ObjectID) error {
+	var err error
+	*id, err = d.appObjectID()
+	if err != nil {
+		return fmt.Errorf("decoding object to delete: %v", err)
+	}
+	return nil
+}
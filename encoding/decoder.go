@@ -97,6 +97,201 @@ func (d *Decoder) tagNumber() (tag uint8, meta tagMeta) {
 	return uint8(meta) >> 4, meta
 }
 
+// peekTag returns the next tag number and meta data without consuming them.
+func (d *Decoder) peekTag() (tag uint8, meta tagMeta) {
+	if d.err != nil || d.len() == 0 {
+		return 0, 0
+	}
+	peek := NewDecoder(d.buff.Bytes())
+	return peek.tagNumber()
+}
+
+// isClosingTag checks to see if the next tag is the closing tag with the given
+// tag number.
+func (d *Decoder) isClosingTag(num uint8) bool {
+	if d.len() == 0 {
+		return false
+	}
+	tag, meta := d.peekTag()
+	return tag == num && meta.isClosing()
+}
+
+// isOpeningTag checks to see if the next tag is the opening tag with the given
+// tag number.
+func (d *Decoder) isOpeningTag(num uint8) bool {
+	if d.len() == 0 {
+		return false
+	}
+	tag, meta := d.peekTag()
+	return tag == num && meta.isOpening()
+}
+
+// isContextTag checks to see if the next tag is a context specific tag, that
+// is neither opening nor closing, with the given tag number.
+func (d *Decoder) isContextTag(num uint8) bool {
+	if d.len() == 0 {
+		return false
+	}
+	tag, meta := d.peekTag()
+	return tag == num && meta.isContextSpecific() && !meta.isOpening() && !meta.isClosing()
+}
+
+// contextTag consumes a context specific tag with the given tag number and
+// returns the length of the value that follows.
+func (d *Decoder) contextTag(num uint8) (uint32, error) {
+	tag, meta, length := d.tagNumberAndValue()
+	if err := d.Error(); err != nil {
+		return 0, err
+	}
+	if tag != num {
+		return 0, &ErrorIncorrectTag{Expected: num, Given: tag}
+	}
+	if !meta.isContextSpecific() {
+		return 0, &ErrorWrongTagType{ContextTag}
+	}
+	return length, nil
+}
+
+// openingTag consumes the opening tag with the given tag number
+func (d *Decoder) openingTag(num uint8) error {
+	tag, meta := d.tagNumber()
+	if err := d.Error(); err != nil {
+		return err
+	}
+	if tag != num {
+		return &ErrorIncorrectTag{Expected: num, Given: tag}
+	}
+	if !meta.isOpening() {
+		return &ErrorWrongTagType{OpeningTag}
+	}
+	return nil
+}
+
+// closingTag consumes the closing tag with the given tag number
+func (d *Decoder) closingTag(num uint8) error {
+	tag, meta := d.tagNumber()
+	if err := d.Error(); err != nil {
+		return err
+	}
+	if tag != num {
+		return &ErrorIncorrectTag{Expected: num, Given: tag}
+	}
+	if !meta.isClosing() {
+		return &ErrorWrongTagType{ClosingTag}
+	}
+	return nil
+}
+
+// rawUntilClosingTag returns the encoded data up to the closing tag with the
+// given tag number. The closing tag is consumed but not returned.
+func (d *Decoder) rawUntilClosingTag(num uint8) ([]byte, error) {
+	start := d.buff.Bytes()
+	depth := 0
+	for {
+		if d.len() == 0 {
+			return nil, fmt.Errorf("missing closing tag %d", num)
+		}
+		consumed := len(start) - d.len()
+		tag, meta, length := d.tagNumberAndValue()
+		if err := d.Error(); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case meta.isOpening():
+			depth++
+		case meta.isClosing():
+			if depth == 0 {
+				if tag != num {
+					return nil, &ErrorIncorrectTag{Expected: num, Given: tag}
+				}
+				raw := make([]byte, consumed)
+				copy(raw, start)
+				return raw, nil
+			}
+			depth--
+		case !meta.isContextSpecific() && tag == tagBool:
+			// Application booleans store their value in the tag
+		default:
+			d.decode(make([]byte, length))
+		}
+	}
+}
+
+// contextUnsigned decodes a context specific unsigned value
+func (d *Decoder) contextUnsigned(num uint8) (uint32, error) {
+	length, err := d.contextTag(num)
+	if err != nil {
+		return 0, err
+	}
+	return d.unsigned(int(length)), d.Error()
+}
+
+// contextEnumerated decodes a context specific enumerated value
+func (d *Decoder) contextEnumerated(num uint8) (uint32, error) {
+	return d.contextUnsigned(num)
+}
+
+// contextBoolean decodes a context specific boolean
+func (d *Decoder) contextBoolean(num uint8) (bool, error) {
+	length, err := d.contextTag(num)
+	if err != nil {
+		return false, err
+	}
+	return d.unsigned(int(length)) > 0, d.Error()
+}
+
+// contextSigned decodes a context specific signed value
+func (d *Decoder) contextSigned(num uint8) (int32, error) {
+	length, err := d.contextTag(num)
+	if err != nil {
+		return 0, err
+	}
+	return d.signed(int(length)), d.Error()
+}
+
+// contextReal decodes a context specific real
+func (d *Decoder) contextReal(num uint8) (float32, error) {
+	if _, err := d.contextTag(num); err != nil {
+		return 0, err
+	}
+	var x float32
+	d.real(&x)
+	return x, d.Error()
+}
+
+// contextBitString decodes a context specific bitstring
+func (d *Decoder) contextBitString(num uint8) (bactype.BitString, error) {
+	length, err := d.contextTag(num)
+	if err != nil {
+		return nil, err
+	}
+	var b bactype.BitString
+	d.bitstring(&b, int(length))
+	return b, d.Error()
+}
+
+// contextString decodes a context specific character string
+func (d *Decoder) contextString(num uint8) (string, error) {
+	length, err := d.contextTag(num)
+	if err != nil {
+		return "", err
+	}
+	var s string
+	// Subtract 1 to length to account for the encoding byte
+	err = d.string(&s, int(length)-1)
+	return s, err
+}
+
+// contextObjectID decodes a context specific object identifier
+func (d *Decoder) contextObjectID(num uint8) (bactype.ObjectID, error) {
+	if _, err := d.contextTag(num); err != nil {
+		return bactype.ObjectID{}, err
+	}
+	objType, instance := d.objectId()
+	return bactype.ObjectID{Type: objType, Instance: instance}, d.Error()
+}
+
 func (d *Decoder) value(meta tagMeta) (value uint32) {
 	if meta.isExtendedValue() {
 		var val uint8
@@ -176,16 +371,13 @@ func (d *Decoder) unsigned(length int) uint32 {
 }
 
 func (d *Decoder) signed24() int32 {
-	var a, b, c int8
-	d.decode(&a)
-	d.decode(&b)
-	d.decode(&c)
+	x := d.unsigned24()
 
-	var x int32
-	x = int32((int32(a) << 16) & 0x00ff0000)
-	x |= int32((int32(b) << 8) & 0x0000ff00)
-	x |= int32(int32(c) & 0x000000ff)
-	return x
+	// Extend the sign bit of the 24 bit value
+	if x&0x800000 != 0 {
+		x |= 0xFF000000
+	}
+	return int32(x)
 }
 
 func (d *Decoder) signed(length int) int32 {
@@ -0,0 +1,147 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// maxPasswordLen is the longest password allowed by the device management
+// services.
+const maxPasswordLen = 20
+
+// DeviceCommunicationControl is a service request to enable or disable the
+// communication of a device.
+func (e *Encoder) DeviceCommunicationControl(invokeID uint8, data bactype.DeviceCommunicationControl) error {
+	if len(data.Password) > maxPasswordLen {
+		return fmt.Errorf("password must be at most %d characters, got %d", maxPasswordLen, len(data.Password))
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedDeviceCommunicationControl,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 (OPTIONAL) - Time Duration
+	if data.Duration != 0 {
+		e.contextUnsigned(0, uint32(data.Duration))
+	}
+
+	// Tag 1 - Enable/Disable
+	e.contextEnumerated(1, uint32(data.State))
+
+	// Tag 2 (OPTIONAL) - Password
+	if data.Password != "" {
+		e.contextString(2, data.Password)
+	}
+	return e.Error()
+}
+
+// DeviceCommunicationControl decodes the service data of a device
+// communication control request.
+func (d *Decoder) DeviceCommunicationControl(data *bactype.DeviceCommunicationControl) error {
+	// Tag 0 (OPTIONAL) - Time Duration
+	data.Duration = 0
+	if d.isContextTag(0) {
+		duration, err := d.contextUnsigned(0)
+		if err != nil {
+			return err
+		}
+		data.Duration = uint16(duration)
+	}
+
+	// Tag 1 - Enable/Disable
+	state, err := d.contextEnumerated(1)
+	if err != nil {
+		return err
+	}
+	data.State = bactype.CommunicationState(state)
+
+	// Tag 2 (OPTIONAL) - Password
+	data.Password = ""
+	if d.isContextTag(2) {
+		data.Password, err = d.contextString(2)
+	}
+	return err
+}
+
+// ReinitializeDevice is a service request to restart a device or to start or
+// end a backup or restore procedure.
+func (e *Encoder) ReinitializeDevice(invokeID uint8, data bactype.ReinitializeDevice) error {
+	if len(data.Password) > maxPasswordLen {
+		return fmt.Errorf("password must be at most %d characters, got %d", maxPasswordLen, len(data.Password))
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedReinitializeDevice,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 - Reinitialized State of Device
+	e.contextEnumerated(0, uint32(data.State))
+
+	// Tag 1 (OPTIONAL) - Password
+	if data.Password != "" {
+		e.contextString(1, data.Password)
+	}
+	return e.Error()
+}
+
+// ReinitializeDevice decodes the service data of a reinitialize device
+// request.
+func (d *Decoder) ReinitializeDevice(data *bactype.ReinitializeDevice) error {
+	// Tag 0 - Reinitialized State of Device
+	state, err := d.contextEnumerated(0)
+	if err != nil {
+		return err
+	}
+	data.State = bactype.ReinitializeState(state)
+
+	// Tag 1 (OPTIONAL) - Password
+	data.Password = ""
+	if d.isContextTag(1) {
+		data.Password, err = d.contextString(1)
+	}
+	return err
+}
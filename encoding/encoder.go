@@ -43,6 +43,11 @@ var EncodingEndian binary.ByteOrder = binary.BigEndian
 type Encoder struct {
 	buff *bytes.Buffer
 	err  error
+
+	// maxSegs is the number of segments of a response accepted by the
+	// confirmed requests that are encoded. Segmented responses are not
+	// accepted when it is 0.
+	maxSegs uint
 }
 
 func NewEncoder() *Encoder {
@@ -53,6 +58,12 @@ func NewEncoder() *Encoder {
 	return &e
 }
 
+// AcceptSegmentedResponse marks the confirmed requests encoded afterwards as
+// accepting a response that is split into up to maxSegs segments.
+func (e *Encoder) AcceptSegmentedResponse(maxSegs uint) {
+	e.maxSegs = maxSegs
+}
+
 func (e *Encoder) Error() error {
 	return e.err
 }
@@ -165,6 +176,36 @@ func (e *Encoder) contextUnsigned(tagNumber uint8, value uint32) {
 	e.unsigned(value)
 }
 
+func (e *Encoder) contextBoolean(tagNumber uint8, value bool) {
+	e.tag(tagInfo{ID: tagNumber, Context: true, Value: 1})
+	if value {
+		e.write(uint8(1))
+	} else {
+		e.write(uint8(0))
+	}
+}
+
+func (e *Encoder) contextSigned(tagNumber uint8, value int32) {
+	e.tag(tagInfo{ID: tagNumber, Context: true, Value: uint32(signedLength(value))})
+	e.signed(value)
+}
+
+func (e *Encoder) contextReal(tagNumber uint8, value float32) {
+	e.tag(tagInfo{ID: tagNumber, Context: true, Value: realLen})
+	e.real(value)
+}
+
+func (e *Encoder) contextBitString(tagNumber uint8, value bactype.BitString) {
+	e.tag(tagInfo{ID: tagNumber, Context: true, Value: bitstringLength(value)})
+	e.bitstring(value)
+}
+
+func (e *Encoder) contextString(tagNumber uint8, value string) {
+	// Add 1 to length to account for the encoding byte
+	e.tag(tagInfo{ID: tagNumber, Context: true, Value: uint32(len(value) + 1)})
+	e.string(value)
+}
+
 func (e *Encoder) enumerated(value uint32) {
 	e.unsigned(value)
 }
@@ -189,3 +230,16 @@ func (e *Encoder) unsigned(value uint32) {
 		e.write(value)
 	}
 }
+
+func (e *Encoder) signed(value int32) {
+	switch signedLength(value) {
+	case size8:
+		e.write(int8(value))
+	case size16:
+		e.write(int16(value))
+	case size24:
+		e.unsigned24(uint32(value))
+	default:
+		e.write(value)
+	}
+}
@@ -0,0 +1,468 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// ConfirmedEventNotification is a confirmed service request that notifies a
+// recipient of an event.
+func (e *Encoder) ConfirmedEventNotification(invokeID uint8, data bactype.EventNotification) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedEventNotification,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	return e.eventNotification(data)
+}
+
+// UnconfirmedEventNotification is an unconfirmed service request that
+// notifies recipients of an event.
+func (e *Encoder) UnconfirmedEventNotification(data bactype.EventNotification) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedEventNotification,
+	}
+	e.APDU(a)
+	return e.eventNotification(data)
+}
+
+func (e *Encoder) eventNotification(data bactype.EventNotification) error {
+	// Tag 0 - Process ID
+	e.contextUnsigned(0, data.ProcessID)
+
+	// Tag 1 - Initiating Device ID
+	e.contextObjectID(1, data.Device.Type, data.Device.Instance)
+
+	// Tag 2 - Event Object ID
+	e.contextObjectID(2, data.Object.Type, data.Object.Instance)
+
+	// Tag 3 - Time Stamp
+	e.timeStamp(3, data.TimeStamp)
+
+	// Tag 4 - Notification Class
+	e.contextUnsigned(4, data.NotificationClass)
+
+	// Tag 5 - Priority
+	e.contextUnsigned(5, uint32(data.Priority))
+
+	// Tag 6 - Event Type
+	e.contextEnumerated(6, uint32(data.EventType))
+
+	// Tag 7 (OPTIONAL) - Message Text
+	if data.MessageText != "" {
+		e.contextString(7, data.MessageText)
+	}
+
+	// Tag 8 - Notify Type
+	e.contextEnumerated(8, uint32(data.NotifyType))
+
+	// Tag 9 and 10 are not sent with ack notifications
+	if data.NotifyType != bactype.NotifyAckNotification {
+		e.contextBoolean(9, data.AckRequired)
+		e.contextEnumerated(10, uint32(data.FromState))
+	}
+
+	// Tag 11 - To State
+	e.contextEnumerated(11, uint32(data.ToState))
+
+	// Tag 12 (OPTIONAL) - Event Values
+	if data.EventValues != nil {
+		e.openingTag(12)
+		if err := e.eventValues(data.EventValues); err != nil {
+			return err
+		}
+		e.closingTag(12)
+	}
+	return e.Error()
+}
+
+// EventNotification decodes the service data of both the confirmed and
+// unconfirmed event notification.
+func (d *Decoder) EventNotification(data *bactype.EventNotification) error {
+	var err error
+
+	// Tag 0 - Process ID
+	if data.ProcessID, err = d.contextUnsigned(0); err != nil {
+		return err
+	}
+
+	// Tag 1 - Initiating Device ID
+	if data.Device, err = d.contextObjectID(1); err != nil {
+		return err
+	}
+
+	// Tag 2 - Event Object ID
+	if data.Object, err = d.contextObjectID(2); err != nil {
+		return err
+	}
+
+	// Tag 3 - Time Stamp
+	if data.TimeStamp, err = d.timeStamp(3); err != nil {
+		return err
+	}
+
+	// Tag 4 - Notification Class
+	if data.NotificationClass, err = d.contextUnsigned(4); err != nil {
+		return err
+	}
+
+	// Tag 5 - Priority
+	priority, err := d.contextUnsigned(5)
+	if err != nil {
+		return err
+	}
+	data.Priority = uint8(priority)
+
+	// Tag 6 - Event Type
+	eventType, err := d.contextEnumerated(6)
+	if err != nil {
+		return err
+	}
+	data.EventType = bactype.EventType(eventType)
+
+	// Tag 7 (OPTIONAL) - Message Text
+	data.MessageText = ""
+	if d.isContextTag(7) {
+		if data.MessageText, err = d.contextString(7); err != nil {
+			return err
+		}
+	}
+
+	// Tag 8 - Notify Type
+	notifyType, err := d.contextEnumerated(8)
+	if err != nil {
+		return err
+	}
+	data.NotifyType = bactype.NotifyType(notifyType)
+
+	// Tag 9 (OPTIONAL) - Ack Required
+	data.AckRequired = false
+	if d.isContextTag(9) {
+		if data.AckRequired, err = d.contextBoolean(9); err != nil {
+			return err
+		}
+	}
+
+	// Tag 10 (OPTIONAL) - From State
+	data.FromState = bactype.EventStateNormal
+	if d.isContextTag(10) {
+		state, err := d.contextEnumerated(10)
+		if err != nil {
+			return err
+		}
+		data.FromState = bactype.EventState(state)
+	}
+
+	// Tag 11 - To State
+	state, err := d.contextEnumerated(11)
+	if err != nil {
+		return err
+	}
+	data.ToState = bactype.EventState(state)
+
+	// Tag 12 (OPTIONAL) - Event Values
+	data.EventValues = nil
+	if d.isOpeningTag(12) {
+		if err = d.openingTag(12); err != nil {
+			return err
+		}
+		if data.EventValues, err = d.eventValues(); err != nil {
+			return err
+		}
+		if err = d.closingTag(12); err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
+
+// timeStamp encodes a BACnetTimeStamp within the given context tag
+func (e *Encoder) timeStamp(tag uint8, ts bactype.TimeStamp) {
+	e.openingTag(tag)
+	e.timeStampChoice(ts)
+	e.closingTag(tag)
+}
+
+func (d *Decoder) timeStamp(tag uint8) (bactype.TimeStamp, error) {
+	if err := d.openingTag(tag); err != nil {
+		return bactype.TimeStamp{}, err
+	}
+	ts, err := d.timeStampChoice()
+	if err != nil {
+		return ts, err
+	}
+	return ts, d.closingTag(tag)
+}
+
+// timeStampChoice encodes a BACnetTimeStamp without an enclosing tag, as used
+// in lists of time stamps.
+func (e *Encoder) timeStampChoice(ts bactype.TimeStamp) {
+	switch ts.Type {
+	case bactype.TimeStampTime:
+		e.tag(tagInfo{ID: uint8(ts.Type), Context: true, Value: timeLen})
+		e.time(ts.Time)
+	case bactype.TimeStampSequenceNumber:
+		e.contextUnsigned(uint8(ts.Type), ts.SequenceNumber)
+	default:
+		e.openingTag(uint8(bactype.TimeStampDateTime))
+		e.dateTime(ts.DateTime)
+		e.closingTag(uint8(bactype.TimeStampDateTime))
+	}
+}
+
+func (d *Decoder) timeStampChoice() (bactype.TimeStamp, error) {
+	var ts bactype.TimeStamp
+	choice, _ := d.peekTag()
+	ts.Type = bactype.TimeStampType(choice)
+	switch ts.Type {
+	case bactype.TimeStampTime:
+		if _, err := d.contextTag(choice); err != nil {
+			return ts, err
+		}
+		d.time(&ts.Time)
+	case bactype.TimeStampSequenceNumber:
+		var err error
+		if ts.SequenceNumber, err = d.contextUnsigned(choice); err != nil {
+			return ts, err
+		}
+	case bactype.TimeStampDateTime:
+		if err := d.openingTag(choice); err != nil {
+			return ts, err
+		}
+		if err := d.dateTime(&ts.DateTime); err != nil {
+			return ts, err
+		}
+		if err := d.closingTag(choice); err != nil {
+			return ts, err
+		}
+	default:
+		return ts, fmt.Errorf("unknown time stamp tag %d", choice)
+	}
+	return ts, d.Error()
+}
+
+// eventValues encodes the notification parameters of an event
+func (e *Encoder) eventValues(values interface{}) error {
+	switch v := values.(type) {
+	case bactype.ChangeOfBitstringParameters:
+		tag := uint8(bactype.EventChangeOfBitstring)
+		e.openingTag(tag)
+		e.contextBitString(0, v.ReferencedBitstring)
+		e.contextBitString(1, v.StatusFlags)
+		e.closingTag(tag)
+	case bactype.ChangeOfStateParameters:
+		tag := uint8(bactype.EventChangeOfState)
+		e.openingTag(tag)
+		e.openingTag(0)
+		e.contextUnsigned(v.NewState.Type, v.NewState.Value)
+		e.closingTag(0)
+		e.contextBitString(1, v.StatusFlags)
+		e.closingTag(tag)
+	case bactype.ChangeOfValueParameters:
+		tag := uint8(bactype.EventChangeOfValue)
+		e.openingTag(tag)
+		e.openingTag(0)
+		switch n := v.NewValue.(type) {
+		case bactype.BitString:
+			e.contextBitString(0, n)
+		case float32:
+			e.contextReal(1, n)
+		default:
+			return fmt.Errorf("change of value must be a bitstring or a float32, got %T", v.NewValue)
+		}
+		e.closingTag(0)
+		e.contextBitString(1, v.StatusFlags)
+		e.closingTag(tag)
+	case bactype.CommandFailureParameters:
+		tag := uint8(bactype.EventCommandFailure)
+		e.openingTag(tag)
+		e.openingTag(0)
+		e.propertyValue(v.CommandValue)
+		e.closingTag(0)
+		e.contextBitString(1, v.StatusFlags)
+		e.openingTag(2)
+		e.propertyValue(v.FeedbackValue)
+		e.closingTag(2)
+		e.closingTag(tag)
+	case bactype.FloatingLimitParameters:
+		tag := uint8(bactype.EventFloatingLimit)
+		e.openingTag(tag)
+		e.contextReal(0, v.ReferenceValue)
+		e.contextBitString(1, v.StatusFlags)
+		e.contextReal(2, v.SetpointValue)
+		e.contextReal(3, v.ErrorLimit)
+		e.closingTag(tag)
+	case bactype.OutOfRangeParameters:
+		tag := uint8(bactype.EventOutOfRange)
+		e.openingTag(tag)
+		e.contextReal(0, v.ExceedingValue)
+		e.contextBitString(1, v.StatusFlags)
+		e.contextReal(2, v.Deadband)
+		e.contextReal(3, v.ExceededLimit)
+		e.closingTag(tag)
+	case bactype.UnsignedRangeParameters:
+		tag := uint8(bactype.EventUnsignedRange)
+		e.openingTag(tag)
+		e.contextUnsigned(0, v.ExceedingValue)
+		e.contextBitString(1, v.StatusFlags)
+		e.contextUnsigned(2, v.ExceededLimit)
+		e.closingTag(tag)
+	case bactype.RawParameters:
+		e.openingTag(v.Tag)
+		e.write(v.Data)
+		e.closingTag(v.Tag)
+	default:
+		return fmt.Errorf("unsupported event values %T", values)
+	}
+	return e.Error()
+}
+
+// eventValues decodes the notification parameters of an event. Event types
+// that are not supported are returned as RawParameters.
+func (d *Decoder) eventValues() (interface{}, error) {
+	tag, _ := d.peekTag()
+	if err := d.openingTag(tag); err != nil {
+		return nil, err
+	}
+
+	var values interface{}
+	var err error
+	switch bactype.EventType(tag) {
+	case bactype.EventChangeOfBitstring:
+		var v bactype.ChangeOfBitstringParameters
+		if v.ReferencedBitstring, err = d.contextBitString(0); err != nil {
+			return nil, err
+		}
+		v.StatusFlags, err = d.contextBitString(1)
+		values = v
+	case bactype.EventChangeOfState:
+		var v bactype.ChangeOfStateParameters
+		if err = d.openingTag(0); err != nil {
+			return nil, err
+		}
+		v.NewState.Type, _ = d.peekTag()
+		if v.NewState.Value, err = d.contextUnsigned(v.NewState.Type); err != nil {
+			return nil, err
+		}
+		if err = d.closingTag(0); err != nil {
+			return nil, err
+		}
+		v.StatusFlags, err = d.contextBitString(1)
+		values = v
+	case bactype.EventChangeOfValue:
+		var v bactype.ChangeOfValueParameters
+		if err = d.openingTag(0); err != nil {
+			return nil, err
+		}
+		if d.isContextTag(0) {
+			v.NewValue, err = d.contextBitString(0)
+		} else {
+			v.NewValue, err = d.contextReal(1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = d.closingTag(0); err != nil {
+			return nil, err
+		}
+		v.StatusFlags, err = d.contextBitString(1)
+		values = v
+	case bactype.EventCommandFailure:
+		var v bactype.CommandFailureParameters
+		if err = d.openingTag(0); err != nil {
+			return nil, err
+		}
+		if v.CommandValue, err = d.propertyValue(0); err != nil {
+			return nil, err
+		}
+		if v.StatusFlags, err = d.contextBitString(1); err != nil {
+			return nil, err
+		}
+		if err = d.openingTag(2); err != nil {
+			return nil, err
+		}
+		v.FeedbackValue, err = d.propertyValue(2)
+		values = v
+	case bactype.EventFloatingLimit:
+		var v bactype.FloatingLimitParameters
+		if v.ReferenceValue, err = d.contextReal(0); err != nil {
+			return nil, err
+		}
+		if v.StatusFlags, err = d.contextBitString(1); err != nil {
+			return nil, err
+		}
+		if v.SetpointValue, err = d.contextReal(2); err != nil {
+			return nil, err
+		}
+		v.ErrorLimit, err = d.contextReal(3)
+		values = v
+	case bactype.EventOutOfRange:
+		var v bactype.OutOfRangeParameters
+		if v.ExceedingValue, err = d.contextReal(0); err != nil {
+			return nil, err
+		}
+		if v.StatusFlags, err = d.contextBitString(1); err != nil {
+			return nil, err
+		}
+		if v.Deadband, err = d.contextReal(2); err != nil {
+			return nil, err
+		}
+		v.ExceededLimit, err = d.contextReal(3)
+		values = v
+	case bactype.EventUnsignedRange:
+		var v bactype.UnsignedRangeParameters
+		if v.ExceedingValue, err = d.contextUnsigned(0); err != nil {
+			return nil, err
+		}
+		if v.StatusFlags, err = d.contextBitString(1); err != nil {
+			return nil, err
+		}
+		v.ExceededLimit, err = d.contextUnsigned(2)
+		values = v
+	default:
+		// The closing tag is consumed along with the raw data
+		raw, err := d.rawUntilClosingTag(tag)
+		return bactype.RawParameters{Tag: tag, Data: raw}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values, d.closingTag(tag)
+}
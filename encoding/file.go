@@ -0,0 +1,262 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Tags of the access method choice of the file services
+const (
+	streamAccessTag uint8 = 0
+	recordAccessTag uint8 = 1
+)
+
+func accessTag(record bool) uint8 {
+	if record {
+		return recordAccessTag
+	}
+	return streamAccessTag
+}
+
+// AtomicReadFile is a service request to read part of a file object.
+func (e *Encoder) AtomicReadFile(invokeID uint8, data bactype.AtomicReadFile) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedAtomicReadFile,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	e.AppData(data.File)
+	tag := accessTag(data.Record)
+	e.openingTag(tag)
+	e.AppData(data.Start)
+	e.AppData(data.Count)
+	e.closingTag(tag)
+	return e.Error()
+}
+
+// AtomicReadFile decodes the service data of an atomic read file request.
+func (d *Decoder) AtomicReadFile(data *bactype.AtomicReadFile) error {
+	var err error
+	data.File, err = d.appObjectID()
+	if err != nil {
+		return err
+	}
+
+	tag, err := d.accessMethod(&data.Record)
+	if err != nil {
+		return err
+	}
+	if data.Start, err = d.appSigned(); err != nil {
+		return err
+	}
+	if data.Count, err = d.appUnsigned(); err != nil {
+		return err
+	}
+	return d.closingTag(tag)
+}
+
+// AtomicReadFileAck is the response made to an atomic read file request.
+func (e *Encoder) AtomicReadFileAck(invokeID uint8, data bactype.AtomicReadFileAck) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedAtomicReadFile,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+
+	e.AppData(data.EndOfFile)
+	tag := accessTag(data.Record)
+	e.openingTag(tag)
+	e.AppData(data.Start)
+	if data.Record {
+		e.AppData(uint32(len(data.Records)))
+		for _, r := range data.Records {
+			e.AppData(r)
+		}
+	} else {
+		e.AppData(data.Data)
+	}
+	e.closingTag(tag)
+	return e.Error()
+}
+
+// AtomicReadFileAck decodes the service data of an atomic read file ack.
+func (d *Decoder) AtomicReadFileAck(data *bactype.AtomicReadFileAck) error {
+	v, err := d.AppData()
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if data.EndOfFile, ok = v.(bool); !ok {
+		return fmt.Errorf("expected end of file to be a boolean but got %T", v)
+	}
+
+	tag, err := d.accessMethod(&data.Record)
+	if err != nil {
+		return err
+	}
+	if data.Start, err = d.appSigned(); err != nil {
+		return err
+	}
+
+	if data.Record {
+		var count uint32
+		if count, err = d.appUnsigned(); err != nil {
+			return err
+		}
+		data.Records, err = d.fileRecords(count)
+	} else {
+		data.Data, err = d.appOctetString()
+	}
+	if err != nil {
+		return err
+	}
+	return d.closingTag(tag)
+}
+
+// AtomicWriteFile is a service request to write part of a file object.
+func (e *Encoder) AtomicWriteFile(invokeID uint8, data bactype.AtomicWriteFile) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedAtomicWriteFile,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	e.AppData(data.File)
+	tag := accessTag(data.Record)
+	e.openingTag(tag)
+	e.AppData(data.Start)
+	if data.Record {
+		e.AppData(uint32(len(data.Records)))
+		for _, r := range data.Records {
+			e.AppData(r)
+		}
+	} else {
+		e.AppData(data.Data)
+	}
+	e.closingTag(tag)
+	return e.Error()
+}
+
+// AtomicWriteFile decodes the service data of an atomic write file request.
+func (d *Decoder) AtomicWriteFile(data *bactype.AtomicWriteFile) error {
+	var err error
+	data.File, err = d.appObjectID()
+	if err != nil {
+		return err
+	}
+
+	tag, err := d.accessMethod(&data.Record)
+	if err != nil {
+		return err
+	}
+	if data.Start, err = d.appSigned(); err != nil {
+		return err
+	}
+
+	if data.Record {
+		var count uint32
+		if count, err = d.appUnsigned(); err != nil {
+			return err
+		}
+		data.Records, err = d.fileRecords(count)
+	} else {
+		data.Data, err = d.appOctetString()
+	}
+	if err != nil {
+		return err
+	}
+	return d.closingTag(tag)
+}
+
+// AtomicWriteFileAck is the response made to an atomic write file request.
+func (e *Encoder) AtomicWriteFileAck(invokeID uint8, data bactype.AtomicWriteFileAck) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedAtomicWriteFile,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+	e.contextSigned(accessTag(data.Record), data.Start)
+	return e.Error()
+}
+
+// AtomicWriteFileAck decodes the service data of an atomic write file ack.
+func (d *Decoder) AtomicWriteFileAck(data *bactype.AtomicWriteFileAck) error {
+	tag, _ := d.peekTag()
+	if tag != streamAccessTag && tag != recordAccessTag {
+		return fmt.Errorf("unknown file access tag %d", tag)
+	}
+	data.Record = tag == recordAccessTag
+
+	var err error
+	data.Start, err = d.contextSigned(tag)
+	return err
+}
+
+// accessMethod consumes the opening tag of the access method choice and
+// returns its tag number.
+func (d *Decoder) accessMethod(record *bool) (uint8, error) {
+	tag, _ := d.peekTag()
+	if tag != streamAccessTag && tag != recordAccessTag {
+		return 0, fmt.Errorf("unknown file access tag %d", tag)
+	}
+	*record = tag == recordAccessTag
+	return tag, d.openingTag(tag)
+}
+
+func (d *Decoder) fileRecords(count uint32) ([][]byte, error) {
+	records := make([][]byte, count)
+	for i := range records {
+		var err error
+		if records[i], err = d.appOctetString(); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
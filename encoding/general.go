@@ -45,6 +45,20 @@ func valueLength(value uint32) int {
 	return size32
 }
 
+// signedLength calculates the smallest number of bytes the signed value can be
+// stored in
+func signedLength(value int32) int {
+	/* length of signed is variable, as per 20.2.5 */
+	if value >= -128 && value < 128 {
+		return size8
+	} else if value >= -32768 && value < 32768 {
+		return size16
+	} else if value >= -8388608 && value < 8388608 {
+		return size24
+	}
+	return size32
+}
+
 /* from clause 20.2.1.3.2 Constructed Data */
 /* true if the tag is an opening tag */
 func isOpeningTag(x uint8) bool {
@@ -82,7 +96,7 @@ func (t *tagMeta) setOpening() {
 }
 
 func (t *tagMeta) isOpening() bool {
-	return ((*t & openingMask) == openingMask)
+	return ((*t & tagMask) == openingMask)
 }
 
 func (t *tagMeta) Clear() {
@@ -0,0 +1,354 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// AddListElement is a service request to add elements to a list property.
+func (e *Encoder) AddListElement(invokeID uint8, data bactype.ListElement) error {
+	return e.listElement(invokeID, bactype.ServiceConfirmedAddListElement, data)
+}
+
+// RemoveListElement is a service request to remove elements from a list
+// property.
+func (e *Encoder) RemoveListElement(invokeID uint8, data bactype.ListElement) error {
+	return e.listElement(invokeID, bactype.ServiceConfirmedRemoveListElement, data)
+}
+
+func (e *Encoder) listElement(invokeID uint8, service bactype.ServiceConfirmed, data bactype.ListElement) error {
+	if err := isValidObjectType(data.Object.Type); err != nil {
+		return err
+	}
+	if err := isValidPropertyType(data.Property); err != nil {
+		return err
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          service,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	// Tag 0 to 2 - Object ID, Property ID and Array Index
+	e.objectPropertyReference(bactype.ObjectPropertyReference{
+		Object:     data.Object,
+		Property:   data.Property,
+		ArrayIndex: data.ArrayIndex,
+	})
+
+	// Tag 3 - List of Elements
+	e.openingTag(3)
+	for _, elem := range data.Elements {
+		if err := e.listElementValue(data.Property, elem); err != nil {
+			return err
+		}
+	}
+	e.closingTag(3)
+	return e.Error()
+}
+
+// AddListElement decodes the service data of an add list element request.
+func (d *Decoder) AddListElement(data *bactype.ListElement) error {
+	return d.listElement(data)
+}
+
+// RemoveListElement decodes the service data of a remove list element
+// request.
+func (d *Decoder) RemoveListElement(data *bactype.ListElement) error {
+	return d.listElement(data)
+}
+
+func (d *Decoder) listElement(data *bactype.ListElement) error {
+	var ref bactype.ObjectPropertyReference
+	err := d.objectPropertyReference(&ref)
+	if err != nil {
+		return err
+	}
+	data.Object = ref.Object
+	data.Property = ref.Property
+	data.ArrayIndex = ref.ArrayIndex
+
+	// Tag 3 - List of Elements
+	if err = d.openingTag(3); err != nil {
+		return err
+	}
+	data.Elements = make([]interface{}, 0)
+	for !d.isClosingTag(3) {
+		if d.len() == 0 {
+			return fmt.Errorf("missing closing tag 3 of list of elements")
+		}
+		elem, err := d.listElementValue(data.Property)
+		if err != nil {
+			return err
+		}
+		data.Elements = append(data.Elements, elem)
+	}
+	return d.closingTag(3)
+}
+
+// ChangeListError decodes the number of the first element that could not be
+// added or removed, which follows the error class and code of a change list
+// error.
+func (d *Decoder) ChangeListError(firstFailed *uint32) error {
+	var err error
+	*firstFailed, err = d.contextUnsigned(1)
+	return err
+}
+
+// listElementValue encodes a single element of a list. Elements of a
+// Recipient_List or Date_List are encoded with their context tags, everything
+// else is encoded as application data.
+func (e *Encoder) listElementValue(prop uint32, elem interface{}) error {
+	switch v := elem.(type) {
+	case bactype.Destination:
+		return e.destination(v)
+	case bactype.Date:
+		if prop != property.DateList {
+			return e.AppData(v)
+		}
+		e.tag(tagInfo{ID: 0, Context: true, Value: dateLen})
+		e.date(v)
+	case bactype.DateRange:
+		e.openingTag(1)
+		e.AppData(v.Start)
+		e.AppData(v.End)
+		e.closingTag(1)
+	case bactype.WeekNDay:
+		e.tag(tagInfo{ID: 2, Context: true, Value: 3})
+		e.write(v.Month)
+		e.write(v.WeekOfMonth)
+		e.write(uint8(v.DayOfWeek))
+	default:
+		return e.AppData(v)
+	}
+	return e.Error()
+}
+
+func (d *Decoder) listElementValue(prop uint32) (interface{}, error) {
+	switch prop {
+	case property.RecipientList:
+		return d.destination()
+	case property.DateList:
+		return d.calendarEntry()
+	default:
+		return d.AppData()
+	}
+}
+
+func (e *Encoder) destination(dest bactype.Destination) error {
+	e.AppData(dest.ValidDays)
+	e.AppData(dest.FromTime)
+	e.AppData(dest.ToTime)
+	if err := e.recipient(dest.Recipient); err != nil {
+		return err
+	}
+	e.AppData(dest.ProcessID)
+	e.AppData(dest.IssueConfirmed)
+	e.AppData(dest.Transitions)
+	return e.Error()
+}
+
+func (d *Decoder) destination() (bactype.Destination, error) {
+	var dest bactype.Destination
+	var ok bool
+
+	v, err := d.AppData()
+	if err != nil {
+		return dest, err
+	}
+	if dest.ValidDays, ok = v.(bactype.BitString); !ok {
+		return dest, fmt.Errorf("expected valid days to be a bitstring but got %T", v)
+	}
+
+	if v, err = d.AppData(); err != nil {
+		return dest, err
+	}
+	if dest.FromTime, ok = v.(bactype.Time); !ok {
+		return dest, fmt.Errorf("expected from time to be a time but got %T", v)
+	}
+	if v, err = d.AppData(); err != nil {
+		return dest, err
+	}
+	if dest.ToTime, ok = v.(bactype.Time); !ok {
+		return dest, fmt.Errorf("expected to time to be a time but got %T", v)
+	}
+
+	if dest.Recipient, err = d.recipient(); err != nil {
+		return dest, err
+	}
+	if dest.ProcessID, err = d.appUnsigned(); err != nil {
+		return dest, err
+	}
+
+	if v, err = d.AppData(); err != nil {
+		return dest, err
+	}
+	if dest.IssueConfirmed, ok = v.(bool); !ok {
+		return dest, fmt.Errorf("expected issue confirmed to be a boolean but got %T", v)
+	}
+
+	if v, err = d.AppData(); err != nil {
+		return dest, err
+	}
+	if dest.Transitions, ok = v.(bactype.BitString); !ok {
+		return dest, fmt.Errorf("expected transitions to be a bitstring but got %T", v)
+	}
+	return dest, nil
+}
+
+// recipient encodes the device or address choice of a recipient
+func (e *Encoder) recipient(r bactype.Recipient) error {
+	switch {
+	case r.Device != nil:
+		e.contextObjectID(0, r.Device.Type, r.Device.Instance)
+	case r.Address != nil:
+		e.openingTag(1)
+		e.address(*r.Address)
+		e.closingTag(1)
+	default:
+		return fmt.Errorf("recipient has neither a device nor an address")
+	}
+	return e.Error()
+}
+
+func (d *Decoder) recipient() (bactype.Recipient, error) {
+	var r bactype.Recipient
+	if d.isContextTag(0) {
+		id, err := d.contextObjectID(0)
+		if err != nil {
+			return r, err
+		}
+		r.Device = &id
+		return r, nil
+	}
+
+	if err := d.openingTag(1); err != nil {
+		return r, err
+	}
+	addr, err := d.address()
+	if err != nil {
+		return r, err
+	}
+	r.Address = &addr
+	return r, d.closingTag(1)
+}
+
+// address encodes a BACnetAddress, the network number followed by the mac
+// address on that network. The local network is network 0.
+func (e *Encoder) address(addr bactype.Address) {
+	e.AppData(uint32(addr.Net))
+	if addr.Net == 0 {
+		e.AppData(addr.Mac)
+	} else {
+		e.AppData(addr.Adr)
+	}
+}
+
+func (d *Decoder) address() (bactype.Address, error) {
+	var addr bactype.Address
+	net, err := d.appUnsigned()
+	if err != nil {
+		return addr, err
+	}
+	mac, err := d.appOctetString()
+	if err != nil {
+		return addr, err
+	}
+
+	addr.Net = uint16(net)
+	if addr.Net == 0 {
+		addr.Mac = mac
+		addr.MacLen = uint8(len(mac))
+	} else {
+		addr.Adr = mac
+		addr.Len = uint8(len(mac))
+	}
+	return addr, nil
+}
+
+// calendarEntry decodes an entry of a Date_List which is a Date, DateRange
+// or WeekNDay.
+func (d *Decoder) calendarEntry() (interface{}, error) {
+	tag, _ := d.peekTag()
+	switch tag {
+	case 0:
+		if _, err := d.contextTag(0); err != nil {
+			return nil, err
+		}
+		var date bactype.Date
+		d.date(&date)
+		return date, d.Error()
+	case 1:
+		var r bactype.DateRange
+		if err := d.openingTag(1); err != nil {
+			return nil, err
+		}
+		start, err := d.AppData()
+		if err != nil {
+			return nil, err
+		}
+		end, err := d.AppData()
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if r.Start, ok = start.(bactype.Date); !ok {
+			return nil, fmt.Errorf("expected start of date range to be a date but got %T", start)
+		}
+		if r.End, ok = end.(bactype.Date); !ok {
+			return nil, fmt.Errorf("expected end of date range to be a date but got %T", end)
+		}
+		return r, d.closingTag(1)
+	case 2:
+		if _, err := d.contextTag(2); err != nil {
+			return nil, err
+		}
+		var w bactype.WeekNDay
+		var day uint8
+		d.decode(&w.Month)
+		d.decode(&w.WeekOfMonth)
+		d.decode(&day)
+		w.DayOfWeek = bactype.DayOfWeek(day)
+		return w, d.Error()
+	default:
+		return nil, fmt.Errorf("unknown calendar entry tag %d", tag)
+	}
+}
@@ -0,0 +1,150 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// ConfirmedPrivateTransfer is a confirmed service request for a proprietary
+// service of a vendor.
+func (e *Encoder) ConfirmedPrivateTransfer(invokeID uint8, data bactype.PrivateTransfer) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedPrivateTransfer,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	return e.privateTransfer(data)
+}
+
+// UnconfirmedPrivateTransfer is an unconfirmed service request for a
+// proprietary service of a vendor.
+func (e *Encoder) UnconfirmedPrivateTransfer(data bactype.PrivateTransfer) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedPrivateTransfer,
+	}
+	e.APDU(a)
+	return e.privateTransfer(data)
+}
+
+// PrivateTransferAck is the response made to a confirmed private transfer
+// request. Data holds the result block.
+func (e *Encoder) PrivateTransferAck(invokeID uint8, data bactype.PrivateTransfer) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedPrivateTransfer,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+	return e.privateTransfer(data)
+}
+
+func (e *Encoder) privateTransfer(data bactype.PrivateTransfer) error {
+	// Tag 0 - Vendor ID
+	e.contextUnsigned(0, data.VendorID)
+
+	// Tag 1 - Service Number
+	e.contextUnsigned(1, data.ServiceNumber)
+
+	// Tag 2 (OPTIONAL) - Service Parameters or Result Block
+	if data.Data != nil {
+		e.openingTag(2)
+		e.write(data.Data)
+		e.closingTag(2)
+	}
+	return e.Error()
+}
+
+// PrivateTransfer decodes the service data of confirmed and unconfirmed
+// private transfer requests, as well as the acknowledgment of a confirmed
+// request.
+func (d *Decoder) PrivateTransfer(data *bactype.PrivateTransfer) error {
+	var err error
+
+	// Tag 0 - Vendor ID
+	if data.VendorID, err = d.contextUnsigned(0); err != nil {
+		return err
+	}
+
+	// Tag 1 - Service Number
+	if data.ServiceNumber, err = d.contextUnsigned(1); err != nil {
+		return err
+	}
+
+	// Tag 2 (OPTIONAL) - Service Parameters or Result Block
+	data.Data = nil
+	if d.isOpeningTag(2) {
+		if err = d.openingTag(2); err != nil {
+			return err
+		}
+		if data.Data, err = d.rawUntilClosingTag(2); err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
+
+// PrivateTransferError decodes the vendor, service number and error
+// parameters that follow the error class and code of a private transfer
+// error.
+func (d *Decoder) PrivateTransferError(data *bactype.PrivateTransfer) error {
+	var err error
+
+	// Tag 1 - Vendor ID
+	if data.VendorID, err = d.contextUnsigned(1); err != nil {
+		return err
+	}
+
+	// Tag 2 - Service Number
+	if data.ServiceNumber, err = d.contextUnsigned(2); err != nil {
+		return err
+	}
+
+	// Tag 3 (OPTIONAL) - Error Parameters
+	data.Data = nil
+	if d.isOpeningTag(3) {
+		if err = d.openingTag(3); err != nil {
+			return err
+		}
+		if data.Data, err = d.rawUntilClosingTag(3); err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
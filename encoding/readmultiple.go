@@ -55,3 +55,48 @@ func (e *Encoder) properties(properties []bactype.Property) error {
 	}
 	return nil
 }
+
+// ReadMultipleProperty decodes a read property multiple request. Properties
+// without an array index have it set to ArrayAll.
+func (d *Decoder) ReadMultipleProperty(data *bactype.ReadMultipleProperty) error {
+	data.Objects = []bactype.Object{}
+	for d.len() > 0 {
+		var obj bactype.Object
+
+		// Tag 0 - Object ID
+		if _, err := d.contextTag(0); err != nil {
+			return err
+		}
+		obj.ID.Type, obj.ID.Instance = d.objectId()
+
+		// Tag 1 - List of Property References
+		if err := d.openingTag(1); err != nil {
+			return err
+		}
+		for d.len() > 0 && !d.isClosingTag(1) {
+			prop := bactype.Property{ArrayIndex: ArrayAll}
+
+			// Tag 0 - Property ID
+			length, err := d.contextTag(0)
+			if err != nil {
+				return err
+			}
+			prop.Type = d.enumerated(int(length))
+
+			// Tag 1 (OPTIONAL) - Array Index
+			if d.isContextTag(1) {
+				length, err = d.contextTag(1)
+				if err != nil {
+					return err
+				}
+				prop.ArrayIndex = d.unsigned(int(length))
+			}
+			obj.Properties = append(obj.Properties, prop)
+		}
+		if err := d.closingTag(1); err != nil {
+			return err
+		}
+		data.Objects = append(data.Objects, obj)
+	}
+	return d.Error()
+}
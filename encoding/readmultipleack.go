@@ -30,7 +30,9 @@ func (e *Encoder) objectsWithData(objects []bactype.Object) error {
 		tag = 1
 		e.openingTag(tag)
 
-		e.propertiesWithData(obj.Properties)
+		if err := e.propertiesWithData(obj.Properties); err != nil {
+			return err
+		}
 
 		// Tag 1 - Closing Tag
 		e.closingTag(tag)
@@ -51,14 +53,19 @@ func (e *Encoder) propertiesWithData(properties []bactype.Property) error {
 			e.contextUnsigned(tag, prop.ArrayIndex)
 		}
 
-		// Tag 4 Opening Tag
+		// Tag 4 - Property Value or Tag 5 - Property Access Error
 		tag++
-		openedTag := tag
-		e.openingTag(openedTag)
-		e.write(prop.Data)
-		e.closingTag(openedTag)
-
-		e.write(prop.Data)
+		if prop.Error != nil {
+			tag++
+			e.openingTag(tag)
+			e.AppData(bactype.Enumerated(prop.Error.Class))
+			e.AppData(bactype.Enumerated(prop.Error.Code))
+			e.closingTag(tag)
+			continue
+		}
+		e.openingTag(tag)
+		e.propertyValue(prop.Data)
+		e.closingTag(tag)
 	}
 	return e.Error()
 }
@@ -71,14 +78,14 @@ func (d *Decoder) ReadMultiplePropertyAck(data *bactype.ReadMultipleProperty) er
 	return d.Error()
 }
 
-func (d *Decoder) bacError(errorClass, errorCode *uint32) error {
+func (d *Decoder) bacError(errorClass *bactype.ErrorClass, errorCode *bactype.ErrorCode) error {
 	data, err := d.AppData()
 	if err != nil {
 		return err
 	}
 	switch val := data.(type) {
 	case uint32:
-		*errorClass = val
+		*errorClass = bactype.ErrorClass(val)
 	default:
 		return fmt.Errorf("Receive bacnet error of unknown type")
 	}
@@ -89,7 +96,7 @@ func (d *Decoder) bacError(errorClass, errorCode *uint32) error {
 	}
 	switch val := data.(type) {
 	case uint32:
-		*errorCode = val
+		*errorCode = bactype.ErrorCode(val)
 	default:
 		return fmt.Errorf("Receive bacnet error of unknown type")
 	}
@@ -153,31 +160,39 @@ func (d *Decoder) objectsWithData(objects *[]bactype.Object) error {
 				prop.ArrayIndex = ArrayAll
 			}
 
-			// Tag 4 - Opening Tag
-			expectedTag = 4
-			if tag != expectedTag {
-				if tag == 5 {
-					var class, code uint32
-					err := d.bacError(&class, &code)
+			// Tag 4 - Property Value or Tag 5 - Property Access Error
+			if !meta.isOpening() {
+				return &ErrorWrongTagType{OpeningTag}
+			}
+			switch tag {
+			case 4:
+				// Lists and arrays hold several values
+				var values []interface{}
+				for d.len() > 0 && !d.isClosingTag(4) {
+					data, err := d.AppData()
 					if err != nil {
 						return err
 					}
-					return fmt.Errorf("Class %d Code %d", class, code)
+					values = append(values, data)
 				}
-				return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
-			}
-			if !meta.isOpening() {
-				return &ErrorWrongTagType{OpeningTag}
-			}
-			data, err := d.AppData()
-			if err != nil {
-				return err
+				if len(values) == 1 {
+					prop.Data = values[0]
+				} else {
+					prop.Data = values
+				}
+			case 5:
+				perr := &bactype.PropertyError{}
+				if err := d.bacError(&perr.Class, &perr.Code); err != nil {
+					return err
+				}
+				prop.Error = perr
+			default:
+				return &ErrorIncorrectTag{Expected: 4, Given: tag}
 			}
-			prop.Data = data
 			obj.Properties = append(obj.Properties, prop)
 
+			expectedTag = tag
 			tag, meta = d.tagNumber()
-			expectedTag = 4
 			if tag != expectedTag {
 				return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
 			}
@@ -186,18 +201,6 @@ func (d *Decoder) objectsWithData(objects *[]bactype.Object) error {
 			}
 
 			tag, meta, length = d.tagNumberAndValue()
-			// Tag 5 - (Optional) Error Code
-			expectedTag = 5
-			if tag == expectedTag {
-				// We have an error
-				if !meta.isOpening() {
-					return &ErrorWrongTagType{OpeningTag}
-				}
-				tag, meta = d.tagNumber()
-				if !meta.isClosing() {
-					return &ErrorWrongTagType{ClosingTag}
-				}
-			}
 		}
 		*objects = append(*objects, obj)
 	}
@@ -101,9 +101,8 @@ func (e *Encoder) ReadPropertyAck(invokeID uint8, data bactype.ReadPropertyData)
 	}
 
 	e.openingTag(tagID)
-	tagID++
 	prop := data.Object.Properties[0]
-	e.AppData(prop.Data)
+	e.propertyValue(prop.Data)
 	e.closingTag(tagID)
 	return e.Error()
 }
@@ -0,0 +1,525 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Tags of the range choice of a read range request
+const (
+	rangeByPositionTag       uint8 = 3
+	rangeBySequenceNumberTag uint8 = 6
+	rangeByTimeTag           uint8 = 7
+)
+
+// ReadRange is a service request to read a range of items from a list
+// property.
+func (e *Encoder) ReadRange(invokeID uint8, data bactype.ReadRange) error {
+	if err := isValidObjectType(data.Object.Type); err != nil {
+		return err
+	}
+	if err := isValidPropertyType(data.Property); err != nil {
+		return err
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedReadRange,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	e.readRangeHeader(data.Object, data.Property, data.ArrayIndex)
+
+	switch data.Range {
+	case bactype.RangeAll:
+		// No range reads the whole list
+	case bactype.RangeByPosition:
+		e.openingTag(rangeByPositionTag)
+		e.AppData(data.Reference)
+		e.AppData(data.Count)
+		e.closingTag(rangeByPositionTag)
+	case bactype.RangeBySequenceNumber:
+		e.openingTag(rangeBySequenceNumberTag)
+		e.AppData(data.Reference)
+		e.AppData(data.Count)
+		e.closingTag(rangeBySequenceNumberTag)
+	case bactype.RangeByTime:
+		e.openingTag(rangeByTimeTag)
+		e.dateTime(data.Time)
+		e.AppData(data.Count)
+		e.closingTag(rangeByTimeTag)
+	default:
+		return fmt.Errorf("unknown range type %d", data.Range)
+	}
+	return e.Error()
+}
+
+func (e *Encoder) readRangeHeader(obj bactype.ObjectID, prop uint32, arrayIndex uint32) {
+	// Tag 0 - Object ID
+	e.contextObjectID(0, obj.Type, obj.Instance)
+
+	// Tag 1 - Property ID
+	e.contextEnumerated(1, prop)
+
+	// Tag 2 (OPTIONAL) - Array Index
+	if arrayIndex != ArrayAll {
+		e.contextUnsigned(2, arrayIndex)
+	}
+}
+
+func (d *Decoder) readRangeHeader(obj *bactype.ObjectID, prop *uint32, arrayIndex *uint32) error {
+	var err error
+
+	// Tag 0 - Object ID
+	*obj, err = d.contextObjectID(0)
+	if err != nil {
+		return err
+	}
+
+	// Tag 1 - Property ID
+	*prop, err = d.contextEnumerated(1)
+	if err != nil {
+		return err
+	}
+
+	// Tag 2 (OPTIONAL) - Array Index
+	*arrayIndex = ArrayAll
+	if d.isContextTag(2) {
+		*arrayIndex, err = d.contextUnsigned(2)
+	}
+	return err
+}
+
+// ReadRange decodes the service data of a read range request.
+func (d *Decoder) ReadRange(data *bactype.ReadRange) error {
+	err := d.readRangeHeader(&data.Object, &data.Property, &data.ArrayIndex)
+	if err != nil {
+		return err
+	}
+
+	data.Range = bactype.RangeAll
+	if d.len() == 0 {
+		return d.Error()
+	}
+
+	tag, _ := d.peekTag()
+	if err = d.openingTag(tag); err != nil {
+		return err
+	}
+	switch tag {
+	case rangeByPositionTag, rangeBySequenceNumberTag:
+		data.Range = bactype.RangeByPosition
+		if tag == rangeBySequenceNumberTag {
+			data.Range = bactype.RangeBySequenceNumber
+		}
+		data.Reference, err = d.appUnsigned()
+	case rangeByTimeTag:
+		data.Range = bactype.RangeByTime
+		err = d.dateTime(&data.Time)
+	default:
+		return fmt.Errorf("unknown range tag %d", tag)
+	}
+	if err != nil {
+		return err
+	}
+
+	data.Count, err = d.appSigned()
+	if err != nil {
+		return err
+	}
+	return d.closingTag(tag)
+}
+
+// ReadRangeAck is the response made to a read range request.
+func (e *Encoder) ReadRangeAck(invokeID uint8, data bactype.ReadRangeAck) error {
+	a := bactype.APDU{
+		DataType: bactype.ComplexAck,
+		Service:  bactype.ServiceConfirmedReadRange,
+		MaxSegs:  0,
+		MaxApdu:  MaxAPDU,
+		InvokeId: invokeID,
+	}
+	e.APDU(a)
+	e.readRangeHeader(data.Object, data.Property, data.ArrayIndex)
+
+	// Tag 3 - Result Flags
+	e.contextBitString(3, bactype.BitString{data.FirstItem, data.LastItem, data.MoreItems})
+
+	// Tag 4 - Item Count
+	e.contextUnsigned(4, data.ItemCount)
+
+	// Tag 5 - Item Data
+	e.openingTag(5)
+	for _, item := range data.Items {
+		var err error
+		switch v := item.(type) {
+		case bactype.LogRecord:
+			err = e.logRecord(v)
+		case bactype.EventLogRecord:
+			err = e.eventLogRecord(v)
+		default:
+			err = e.AppData(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.closingTag(5)
+
+	// Tag 6 (OPTIONAL) - First Sequence Number
+	if data.FirstSequenceNumber != 0 {
+		e.contextUnsigned(6, data.FirstSequenceNumber)
+	}
+	return e.Error()
+}
+
+// ReadRangeAck decodes the service data of a read range ack. Items of the
+// Log_Buffer of Trend Logs and Event Logs are decoded into log records.
+func (d *Decoder) ReadRangeAck(data *bactype.ReadRangeAck) error {
+	err := d.readRangeHeader(&data.Object, &data.Property, &data.ArrayIndex)
+	if err != nil {
+		return err
+	}
+
+	// Tag 3 - Result Flags
+	flags, err := d.contextBitString(3)
+	if err != nil {
+		return err
+	}
+	data.FirstItem = flags.Bit(0)
+	data.LastItem = flags.Bit(1)
+	data.MoreItems = flags.Bit(2)
+
+	// Tag 4 - Item Count
+	data.ItemCount, err = d.contextUnsigned(4)
+	if err != nil {
+		return err
+	}
+
+	// Tag 5 - Item Data
+	if err = d.openingTag(5); err != nil {
+		return err
+	}
+	decodeItem := func() (interface{}, error) {
+		return d.AppData()
+	}
+	if data.Property == property.LogBuffer {
+		switch data.Object.Type {
+		case bactype.TrendLog:
+			decodeItem = func() (interface{}, error) {
+				return d.logRecord()
+			}
+		case bactype.EventLog:
+			decodeItem = func() (interface{}, error) {
+				return d.eventLogRecord()
+			}
+		}
+	}
+
+	data.Items = make([]interface{}, 0, data.ItemCount)
+	for !d.isClosingTag(5) {
+		if d.len() == 0 {
+			return fmt.Errorf("missing closing tag 5 of item data")
+		}
+		item, err := decodeItem()
+		if err != nil {
+			return err
+		}
+		data.Items = append(data.Items, item)
+	}
+	if err = d.closingTag(5); err != nil {
+		return err
+	}
+
+	// Tag 6 (OPTIONAL) - First Sequence Number
+	data.FirstSequenceNumber = 0
+	if d.isContextTag(6) {
+		data.FirstSequenceNumber, err = d.contextUnsigned(6)
+		if err != nil {
+			return err
+		}
+	}
+	return d.Error()
+}
+
+// Tags of the log datum choice of a log record
+const (
+	logStatusTag   uint8 = 0
+	logBooleanTag  uint8 = 1
+	logRealTag     uint8 = 2
+	logEnumTag     uint8 = 3
+	logUnsignedTag uint8 = 4
+	logSignedTag   uint8 = 5
+	logBitsTag     uint8 = 6
+	logNullTag     uint8 = 7
+	logFailureTag  uint8 = 8
+	logTimeTag     uint8 = 9
+	logAnyTag      uint8 = 10
+)
+
+func (e *Encoder) logRecord(r bactype.LogRecord) error {
+	// Tag 0 - Timestamp
+	e.openingTag(0)
+	e.dateTime(r.Timestamp)
+	e.closingTag(0)
+
+	// Tag 1 - Log Datum
+	e.openingTag(1)
+	switch v := r.Value.(type) {
+	case bactype.LogStatus:
+		e.contextBitString(logStatusTag, bactype.BitString(v))
+	case bool:
+		e.contextBoolean(logBooleanTag, v)
+	case float32:
+		e.contextReal(logRealTag, v)
+	case bactype.Enumerated:
+		e.contextEnumerated(logEnumTag, uint32(v))
+	case uint32:
+		e.contextUnsigned(logUnsignedTag, v)
+	case int32:
+		e.contextSigned(logSignedTag, v)
+	case bactype.BitString:
+		e.contextBitString(logBitsTag, v)
+	case nil:
+		e.tag(tagInfo{ID: logNullTag, Context: true, Value: 0})
+	case bactype.LogFailure:
+		e.openingTag(logFailureTag)
+		e.AppData(bactype.Enumerated(v.Class))
+		e.AppData(bactype.Enumerated(v.Code))
+		e.closingTag(logFailureTag)
+	case bactype.TimeChange:
+		e.contextReal(logTimeTag, float32(v))
+	case []interface{}:
+		e.openingTag(logAnyTag)
+		for _, x := range v {
+			if err := e.AppData(x); err != nil {
+				return err
+			}
+		}
+		e.closingTag(logAnyTag)
+	default:
+		return fmt.Errorf("unsupported log datum type %T", r.Value)
+	}
+	e.closingTag(1)
+
+	// Tag 2 (OPTIONAL) - Status Flags
+	if r.StatusFlags != nil {
+		e.contextBitString(2, r.StatusFlags)
+	}
+	return e.Error()
+}
+
+func (d *Decoder) logRecord() (bactype.LogRecord, error) {
+	var r bactype.LogRecord
+
+	// Tag 0 - Timestamp
+	if err := d.openingTag(0); err != nil {
+		return r, err
+	}
+	if err := d.dateTime(&r.Timestamp); err != nil {
+		return r, err
+	}
+	if err := d.closingTag(0); err != nil {
+		return r, err
+	}
+
+	// Tag 1 - Log Datum
+	if err := d.openingTag(1); err != nil {
+		return r, err
+	}
+
+	var err error
+	tag, _ := d.peekTag()
+	switch tag {
+	case logStatusTag:
+		var b bactype.BitString
+		b, err = d.contextBitString(tag)
+		r.Value = bactype.LogStatus(b)
+	case logBooleanTag:
+		r.Value, err = d.contextBoolean(tag)
+	case logRealTag:
+		r.Value, err = d.contextReal(tag)
+	case logEnumTag:
+		var x uint32
+		x, err = d.contextEnumerated(tag)
+		r.Value = bactype.Enumerated(x)
+	case logUnsignedTag:
+		r.Value, err = d.contextUnsigned(tag)
+	case logSignedTag:
+		r.Value, err = d.contextSigned(tag)
+	case logBitsTag:
+		r.Value, err = d.contextBitString(tag)
+	case logNullTag:
+		_, err = d.contextTag(tag)
+		r.Value = nil
+	case logFailureTag:
+		var class, code uint32
+		if err = d.openingTag(tag); err != nil {
+			return r, err
+		}
+		if class, err = d.appUnsigned(); err != nil {
+			return r, err
+		}
+		if code, err = d.appUnsigned(); err != nil {
+			return r, err
+		}
+		err = d.closingTag(tag)
+		r.Value = bactype.LogFailure{
+			Class: bactype.ErrorClass(class),
+			Code:  bactype.ErrorCode(code),
+		}
+	case logTimeTag:
+		var x float32
+		x, err = d.contextReal(tag)
+		r.Value = bactype.TimeChange(x)
+	case logAnyTag:
+		if err = d.openingTag(tag); err != nil {
+			return r, err
+		}
+		values := make([]interface{}, 0)
+		for !d.isClosingTag(tag) {
+			if d.len() == 0 {
+				return r, fmt.Errorf("missing closing tag %d of log datum", tag)
+			}
+			var v interface{}
+			if v, err = d.AppData(); err != nil {
+				return r, err
+			}
+			values = append(values, v)
+		}
+		err = d.closingTag(tag)
+		r.Value = values
+	default:
+		return r, fmt.Errorf("unknown log datum tag %d", tag)
+	}
+	if err != nil {
+		return r, err
+	}
+	if err = d.closingTag(1); err != nil {
+		return r, err
+	}
+
+	// Tag 2 (OPTIONAL) - Status Flags
+	if d.isContextTag(2) {
+		r.StatusFlags, err = d.contextBitString(2)
+	}
+	return r, err
+}
+
+// Tags of the log datum choice of an event log record
+const (
+	eventLogStatusTag       uint8 = 0
+	eventLogNotificationTag uint8 = 1
+	eventLogTimeTag         uint8 = 2
+)
+
+func (e *Encoder) eventLogRecord(r bactype.EventLogRecord) error {
+	// Tag 0 - Timestamp
+	e.openingTag(0)
+	e.dateTime(r.Timestamp)
+	e.closingTag(0)
+
+	// Tag 1 - Log Datum
+	e.openingTag(1)
+	switch v := r.Value.(type) {
+	case bactype.LogStatus:
+		e.contextBitString(eventLogStatusTag, bactype.BitString(v))
+	case bactype.EventNotification:
+		e.openingTag(eventLogNotificationTag)
+		if err := e.eventNotification(v); err != nil {
+			return err
+		}
+		e.closingTag(eventLogNotificationTag)
+	case bactype.TimeChange:
+		e.contextReal(eventLogTimeTag, float32(v))
+	default:
+		return fmt.Errorf("unsupported event log datum type %T", r.Value)
+	}
+	e.closingTag(1)
+	return e.Error()
+}
+
+func (d *Decoder) eventLogRecord() (bactype.EventLogRecord, error) {
+	var r bactype.EventLogRecord
+
+	// Tag 0 - Timestamp
+	if err := d.openingTag(0); err != nil {
+		return r, err
+	}
+	if err := d.dateTime(&r.Timestamp); err != nil {
+		return r, err
+	}
+	if err := d.closingTag(0); err != nil {
+		return r, err
+	}
+
+	// Tag 1 - Log Datum
+	if err := d.openingTag(1); err != nil {
+		return r, err
+	}
+
+	var err error
+	tag, _ := d.peekTag()
+	switch tag {
+	case eventLogStatusTag:
+		var b bactype.BitString
+		b, err = d.contextBitString(tag)
+		r.Value = bactype.LogStatus(b)
+	case eventLogNotificationTag:
+		if err = d.openingTag(tag); err != nil {
+			return r, err
+		}
+		var n bactype.EventNotification
+		if err = d.EventNotification(&n); err != nil {
+			return r, err
+		}
+		err = d.closingTag(tag)
+		r.Value = n
+	case eventLogTimeTag:
+		var x float32
+		x, err = d.contextReal(tag)
+		r.Value = bactype.TimeChange(x)
+	default:
+		return r, fmt.Errorf("unknown event log datum tag %d", tag)
+	}
+	if err != nil {
+		return r, err
+	}
+	return r, d.closingTag(1)
+}
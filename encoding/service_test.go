@@ -31,6 +31,7 @@ License.
 package encoding
 
 import (
+	"bytes"
 	"encoding/json"
 	"log"
 	"reflect"
@@ -329,3 +330,1641 @@ func TestReadMultipleThree(t *testing.T) {
 		t.Fatalf("failed to decode read multiple: %v", err)
 	}
 }
+
+func TestWriteProperty(t *testing.T) {
+	wp := bactype.WritePropertyData{
+		Object: bactype.Object{
+			ID: bactype.ObjectID{
+				Type:     bactype.AnalogValue,
+				Instance: 4,
+			},
+			Properties: []bactype.Property{
+				bactype.Property{
+					Type:       85,
+					ArrayIndex: ArrayAll,
+					Data:       float32(72.5),
+				},
+			},
+		},
+		Priority: 8,
+	}
+
+	subTest := func(t *testing.T, wp bactype.WritePropertyData) {
+		e := NewEncoder()
+		err := e.WriteProperty(12, wp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := NewDecoder(e.Bytes())
+		var a bactype.APDU
+		err = d.APDU(&a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedWriteProperty {
+			t.Fatalf("Service should be write property, got %d", a.Service)
+		}
+
+		var out bactype.WritePropertyData
+		err = NewDecoder(a.RawData).WriteProperty(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(wp, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", wp, out)
+		}
+	}
+
+	t.Run("Priority", func(t *testing.T) { subTest(t, wp) })
+
+	wp.Priority = bactype.NoPriority
+	wp.Object.Properties[0].ArrayIndex = 3
+	t.Run("Array Index", func(t *testing.T) { subTest(t, wp) })
+
+	// Relinquishing a command is done by writing null
+	wp.Priority = 16
+	wp.Object.Properties[0].ArrayIndex = ArrayAll
+	wp.Object.Properties[0].Data = nil
+	t.Run("Relinquish", func(t *testing.T) { subTest(t, wp) })
+
+	wp.Object.Properties[0].Data = []interface{}{uint32(1), uint32(2), uint32(3)}
+	t.Run("List", func(t *testing.T) { subTest(t, wp) })
+}
+
+func TestSimpleAck(t *testing.T) {
+	a := bactype.APDU{
+		DataType: bactype.SimpleAck,
+		InvokeId: 3,
+		Service:  bactype.ServiceConfirmedWriteProperty,
+	}
+	e := NewEncoder()
+	e.APDU(a)
+
+	var out bactype.APDU
+	err := NewDecoder(e.Bytes()).APDU(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", a, out)
+	}
+}
+
+func TestWriteMultipleProperty(t *testing.T) {
+	wp := bactype.WriteMultipleProperty{
+		Objects: []bactype.Object{
+			bactype.Object{
+				ID: bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1},
+				Properties: []bactype.Property{
+					bactype.Property{Type: 85, ArrayIndex: ArrayAll, Data: float32(21.5), Priority: 10},
+					bactype.Property{Type: 28, ArrayIndex: ArrayAll, Data: "Zone Setpoint"},
+				},
+			},
+			bactype.Object{
+				ID: bactype.ObjectID{Type: bactype.BinaryValue, Instance: 7},
+				Properties: []bactype.Property{
+					bactype.Property{Type: 85, ArrayIndex: ArrayAll, Data: nil, Priority: 8},
+					bactype.Property{Type: 87, ArrayIndex: 3, Data: bactype.Enumerated(1)},
+				},
+			},
+		},
+	}
+
+	e := NewEncoder()
+	err := e.WriteMultipleProperty(2, wp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(e.Bytes())
+	var a bactype.APDU
+	if err = d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bactype.WriteMultipleProperty
+	err = NewDecoder(a.RawData).WriteMultipleProperty(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Enumerated values are decoded as uint32
+	wp.Objects[1].Properties[1].Data = uint32(1)
+	if !reflect.DeepEqual(wp, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", wp, out)
+	}
+}
+
+func TestWriteMultiplePropertyError(t *testing.T) {
+	ref := bactype.ObjectPropertyReference{
+		Object:     bactype.ObjectID{Type: bactype.AnalogValue, Instance: 3},
+		Property:   85,
+		ArrayIndex: ArrayAll,
+	}
+
+	e := NewEncoder()
+	e.write(bactype.Error)
+	e.write(uint8(9))
+	e.write(bactype.ServiceConfirmedWritePropMultiple)
+	e.openingTag(0)
+	e.AppData(bactype.Enumerated(2))
+	e.AppData(bactype.Enumerated(40))
+	e.closingTag(0)
+	e.openingTag(1)
+	e.objectPropertyReference(ref)
+	e.closingTag(1)
+
+	var a bactype.APDU
+	err := NewDecoder(e.Bytes()).APDU(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Error.Class != 2 || a.Error.Code != 40 {
+		t.Fatalf("Error class and code were not decoded properly: %d %d", a.Error.Class, a.Error.Code)
+	}
+
+	var out bactype.ObjectPropertyReference
+	err = NewDecoder(a.RawData).WriteMultiplePropertyError(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ref, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ref, out)
+	}
+}
+
+func TestSubscribeCOV(t *testing.T) {
+	sub := bactype.SubscribeCOV{
+		ProcessID:      18,
+		Object:         bactype.ObjectID{Type: bactype.AnalogInput, Instance: 2},
+		IssueConfirmed: true,
+		Lifetime:       600,
+	}
+
+	subTest := func(t *testing.T, sub bactype.SubscribeCOV) {
+		e := NewEncoder()
+		if err := e.SubscribeCOV(4, sub); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bactype.SubscribeCOV
+		if err := NewDecoder(a.RawData).SubscribeCOV(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sub, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", sub, out)
+		}
+	}
+	t.Run("Subscribe", func(t *testing.T) { subTest(t, sub) })
+
+	sub = bactype.SubscribeCOV{
+		ProcessID: 18,
+		Object:    sub.Object,
+		Cancel:    true,
+	}
+	t.Run("Cancel", func(t *testing.T) { subTest(t, sub) })
+}
+
+func TestCOVNotification(t *testing.T) {
+	n := bactype.COVNotification{
+		ProcessID:     18,
+		Device:        bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234},
+		Object:        bactype.ObjectID{Type: bactype.AnalogInput, Instance: 2},
+		TimeRemaining: 540,
+		Values: []bactype.Property{
+			bactype.Property{Type: 85, ArrayIndex: ArrayAll, Data: float32(68.25)},
+			bactype.Property{Type: 111, ArrayIndex: ArrayAll,
+				Data: bactype.BitString{false, true, false, false}},
+		},
+	}
+
+	e := NewEncoder()
+	if err := e.UnconfirmedCOVNotification(n); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a.UnconfirmedService != bactype.ServiceUnconfirmedCOVNotification {
+		t.Fatalf("Service should be cov notification, got %d", a.UnconfirmedService)
+	}
+
+	var out bactype.COVNotification
+	if err := NewDecoder(a.RawData).COVNotification(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", n, out)
+	}
+
+	e = NewEncoder()
+	if err := e.ConfirmedCOVNotification(3, n); err != nil {
+		t.Fatal(err)
+	}
+	a = bactype.APDU{}
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	out = bactype.COVNotification{}
+	if err := NewDecoder(a.RawData).COVNotification(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", n, out)
+	}
+}
+
+func TestSubscribeCOVProperty(t *testing.T) {
+	increment := float32(0.5)
+	sub := bactype.SubscribeCOVProperty{
+		SubscribeCOV: bactype.SubscribeCOV{
+			ProcessID:      3,
+			Object:         bactype.ObjectID{Type: bactype.AnalogInput, Instance: 9},
+			IssueConfirmed: false,
+			Lifetime:       300,
+		},
+		Property:     85,
+		ArrayIndex:   ArrayAll,
+		COVIncrement: &increment,
+	}
+
+	subTest := func(t *testing.T, sub bactype.SubscribeCOVProperty) {
+		e := NewEncoder()
+		if err := e.SubscribeCOVProperty(4, sub); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedSubscribeCOVProperty {
+			t.Fatalf("Service should be subscribe cov property, got %d", a.Service)
+		}
+
+		var out bactype.SubscribeCOVProperty
+		if err := NewDecoder(a.RawData).SubscribeCOVProperty(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sub, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", sub, out)
+		}
+	}
+	t.Run("Increment", func(t *testing.T) { subTest(t, sub) })
+
+	// Reliability without an increment
+	sub.Property = 103
+	sub.COVIncrement = nil
+	t.Run("No Increment", func(t *testing.T) { subTest(t, sub) })
+
+	sub.Cancel = true
+	sub.Lifetime = 0
+	sub.ArrayIndex = 2
+	t.Run("Cancel", func(t *testing.T) { subTest(t, sub) })
+}
+
+func TestReadRange(t *testing.T) {
+	rangeTest := func(t *testing.T, rr bactype.ReadRange) {
+		e := NewEncoder()
+		if err := e.ReadRange(6, rr); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedReadRange {
+			t.Fatalf("Service should be read range, got %d", a.Service)
+		}
+
+		var out bactype.ReadRange
+		if err := NewDecoder(a.RawData).ReadRange(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rr, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", rr, out)
+		}
+	}
+
+	rr := bactype.ReadRange{
+		Object:     bactype.ObjectID{Type: bactype.TrendLog, Instance: 1},
+		Property:   131,
+		ArrayIndex: ArrayAll,
+	}
+	t.Run("All", func(t *testing.T) { rangeTest(t, rr) })
+
+	rr.Range = bactype.RangeByPosition
+	rr.Reference = 300
+	rr.Count = -20
+	t.Run("Position", func(t *testing.T) { rangeTest(t, rr) })
+
+	rr.Range = bactype.RangeBySequenceNumber
+	rr.Reference = 70000
+	rr.Count = 100
+	t.Run("Sequence Number", func(t *testing.T) { rangeTest(t, rr) })
+
+	rr.Range = bactype.RangeByTime
+	rr.Reference = 0
+	rr.Time = bactype.DateTime{
+		Date: bactype.Date{Year: 2018, Month: 3, Day: 14, DayOfWeek: bactype.Wednesday},
+		Time: bactype.Time{Hour: 13, Minute: 30, Second: 5, Millisecond: 250},
+	}
+	t.Run("Time", func(t *testing.T) { rangeTest(t, rr) })
+}
+
+func TestReadRangeAck(t *testing.T) {
+	ts := bactype.DateTime{
+		Date: bactype.Date{Year: 2018, Month: 3, Day: 14, DayOfWeek: bactype.Wednesday},
+		Time: bactype.Time{Hour: 13, Minute: 30, Second: 0},
+	}
+	flags := bactype.BitString{false, true, false, false}
+
+	ackTest := func(t *testing.T, ack bactype.ReadRangeAck) {
+		e := NewEncoder()
+		if err := e.ReadRangeAck(7, ack); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		d := NewDecoder(e.Bytes())
+		if err := d.APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.DataType != bactype.ComplexAck || a.InvokeId != 7 {
+			t.Fatalf("Expected a complex ack with invoke id 7, got %v", a)
+		}
+
+		var out bactype.ReadRangeAck
+		if err := d.ReadRangeAck(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ack, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, out)
+		}
+	}
+
+	ack := bactype.ReadRangeAck{
+		Object:     bactype.ObjectID{Type: bactype.TrendLog, Instance: 1},
+		Property:   131,
+		ArrayIndex: ArrayAll,
+		FirstItem:  true,
+		MoreItems:  true,
+		Items: []interface{}{
+			bactype.LogRecord{Timestamp: ts, Value: bactype.LogStatus{false, true, false}},
+			bactype.LogRecord{Timestamp: ts, Value: true, StatusFlags: flags},
+			bactype.LogRecord{Timestamp: ts, Value: float32(72.5), StatusFlags: flags},
+			bactype.LogRecord{Timestamp: ts, Value: bactype.Enumerated(3)},
+			bactype.LogRecord{Timestamp: ts, Value: uint32(70000)},
+			bactype.LogRecord{Timestamp: ts, Value: int32(-12)},
+			bactype.LogRecord{Timestamp: ts, Value: bactype.BitString{true, false, true}},
+			bactype.LogRecord{Timestamp: ts, Value: nil},
+			bactype.LogRecord{Timestamp: ts, Value: bactype.LogFailure{Class: 2, Code: 31}},
+			bactype.LogRecord{Timestamp: ts, Value: bactype.TimeChange(-3600)},
+			bactype.LogRecord{Timestamp: ts, Value: []interface{}{"text", uint32(4)}},
+		},
+		FirstSequenceNumber: 42,
+	}
+	ack.ItemCount = uint32(len(ack.Items))
+	t.Run("Trend Log", func(t *testing.T) { ackTest(t, ack) })
+
+	ack.Object.Type = bactype.EventLog
+	ack.Items = []interface{}{
+		bactype.EventLogRecord{Timestamp: ts, Value: bactype.LogStatus{true, false, false}},
+		bactype.EventLogRecord{Timestamp: ts, Value: bactype.EventNotification{
+			ProcessID:   1,
+			Device:      bactype.ObjectID{Type: bactype.DeviceType, Instance: 10},
+			Object:      bactype.ObjectID{Type: bactype.AnalogInput, Instance: 2},
+			TimeStamp:   bactype.TimeStamp{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 9},
+			EventType:   bactype.EventOutOfRange,
+			NotifyType:  bactype.NotifyAlarm,
+			AckRequired: true,
+			ToState:     bactype.EventStateHighLimit,
+			EventValues: bactype.OutOfRangeParameters{
+				ExceedingValue: 90,
+				StatusFlags:    bactype.BitString{true, false, false, false},
+				Deadband:       1,
+				ExceededLimit:  85,
+			},
+		}},
+		bactype.EventLogRecord{Timestamp: ts, Value: bactype.TimeChange(60)},
+	}
+	ack.ItemCount = uint32(len(ack.Items))
+	t.Run("Event Log", func(t *testing.T) { ackTest(t, ack) })
+
+	ack.Object.Type = bactype.DeviceType
+	ack.Property = 76
+	ack.Items = []interface{}{
+		bactype.ObjectID{Type: bactype.AnalogInput, Instance: 1},
+		bactype.ObjectID{Type: bactype.TrendLog, Instance: 1},
+	}
+	ack.ItemCount = uint32(len(ack.Items))
+	ack.FirstSequenceNumber = 0
+	t.Run("List", func(t *testing.T) { ackTest(t, ack) })
+}
+
+func TestAtomicReadFile(t *testing.T) {
+	file := bactype.ObjectID{Type: bactype.File, Instance: 2}
+	reqs := map[string]bactype.AtomicReadFile{
+		"Stream": {File: file, Start: 1400, Count: 1452},
+		"Record": {File: file, Record: true, Start: 12, Count: 32},
+	}
+	for name, req := range reqs {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.AtomicReadFile(1, req); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			var out bactype.AtomicReadFile
+			if err := NewDecoder(a.RawData).AtomicReadFile(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(req, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+			}
+		})
+	}
+
+	acks := map[string]bactype.AtomicReadFileAck{
+		"Stream Ack": {Start: 1400, Data: []byte("line 1\nline 2\n")},
+		"Empty Ack":  {EndOfFile: true, Start: 0, Data: []byte{}},
+		"Record Ack": {EndOfFile: true, Record: true, Start: 12, Records: [][]byte{[]byte("a,b"), []byte("c,d")}},
+	}
+	for name, ack := range acks {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.AtomicReadFileAck(1, ack); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			d := NewDecoder(e.Bytes())
+			if err := d.APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			var out bactype.AtomicReadFileAck
+			if err := d.AtomicReadFileAck(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ack, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, out)
+			}
+		})
+	}
+}
+
+func TestAtomicWriteFile(t *testing.T) {
+	file := bactype.ObjectID{Type: bactype.File, Instance: 2}
+	reqs := map[string]bactype.AtomicWriteFile{
+		"Stream": {File: file, Start: 0, Data: []byte{0, 1, 2, 3}},
+		"Append": {File: file, Record: true, Start: -1, Records: [][]byte{[]byte("record")}},
+	}
+	for name, req := range reqs {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.AtomicWriteFile(1, req); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			var out bactype.AtomicWriteFile
+			if err := NewDecoder(a.RawData).AtomicWriteFile(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(req, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+			}
+
+			ack := bactype.AtomicWriteFileAck{Record: req.Record, Start: 300}
+			e = NewEncoder()
+			if err := e.AtomicWriteFileAck(1, ack); err != nil {
+				t.Fatal(err)
+			}
+			d := NewDecoder(e.Bytes())
+			if err := d.APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			var outAck bactype.AtomicWriteFileAck
+			if err := d.AtomicWriteFileAck(&outAck); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ack, outAck) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, outAck)
+			}
+		})
+	}
+}
+
+func TestCreateObject(t *testing.T) {
+	createTest := func(t *testing.T, co bactype.CreateObject) {
+		e := NewEncoder()
+		if err := e.CreateObject(3, co); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedCreateObject {
+			t.Fatalf("Service should be create object, got %d", a.Service)
+		}
+
+		var out bactype.CreateObject
+		if err := NewDecoder(a.RawData).CreateObject(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(co, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", co, out)
+		}
+	}
+
+	t.Run("Type", func(t *testing.T) {
+		createTest(t, bactype.CreateObject{Type: bactype.NotificationClass})
+	})
+
+	t.Run("ID", func(t *testing.T) {
+		id := bactype.ObjectID{Type: bactype.AnalogValue, Instance: 12}
+		createTest(t, bactype.CreateObject{
+			Type: bactype.AnalogValue,
+			ID:   &id,
+			Properties: []bactype.Property{
+				{Type: 77, ArrayIndex: ArrayAll, Data: "Setpoint"},
+				{Type: 85, ArrayIndex: ArrayAll, Data: float32(21.5)},
+			},
+		})
+	})
+
+	t.Run("Ack", func(t *testing.T) {
+		id := bactype.ObjectID{Type: bactype.NotificationClass, Instance: 4}
+		e := NewEncoder()
+		if err := e.CreateObjectAck(3, id); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		d := NewDecoder(e.Bytes())
+		if err := d.APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		var out bactype.ObjectID
+		if err := d.CreateObjectAck(&out); err != nil {
+			t.Fatal(err)
+		}
+		if out != id {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", id, out)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		e := NewEncoder()
+		e.write(bactype.Error)
+		e.write(uint8(3))
+		e.write(bactype.ServiceConfirmedCreateObject)
+		e.openingTag(0)
+		e.AppData(bactype.Enumerated(2))
+		e.AppData(bactype.Enumerated(37))
+		e.closingTag(0)
+		e.contextUnsigned(1, 2)
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Error.Class != 2 || a.Error.Code != 37 {
+			t.Fatalf("Error class and code were not decoded properly: %d %d", a.Error.Class, a.Error.Code)
+		}
+		var firstFailed uint32
+		if err := NewDecoder(a.RawData).CreateObjectError(&firstFailed); err != nil {
+			t.Fatal(err)
+		}
+		if firstFailed != 2 {
+			t.Errorf("First failed element should be 2, got %d", firstFailed)
+		}
+	})
+}
+
+func TestDeleteObject(t *testing.T) {
+	id := bactype.ObjectID{Type: bactype.AnalogValue, Instance: 12}
+	e := NewEncoder()
+	if err := e.DeleteObject(5, id); err != nil {
+		t.Fatal(err)
+	}
+
+	var a bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.ObjectID
+	if err := NewDecoder(a.RawData).DeleteObject(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != id {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", id, out)
+	}
+}
+
+func TestListElement(t *testing.T) {
+	device := bactype.ObjectID{Type: bactype.DeviceType, Instance: 1001}
+	elementTest := func(t *testing.T, le bactype.ListElement) {
+		e := NewEncoder()
+		if err := e.AddListElement(2, le); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedAddListElement {
+			t.Fatalf("Service should be add list element, got %d", a.Service)
+		}
+
+		var out bactype.ListElement
+		if err := NewDecoder(a.RawData).AddListElement(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(le, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", le, out)
+		}
+	}
+
+	t.Run("Recipient List", func(t *testing.T) {
+		elementTest(t, bactype.ListElement{
+			Object:     bactype.ObjectID{Type: bactype.NotificationClass, Instance: 1},
+			Property:   102,
+			ArrayIndex: ArrayAll,
+			Elements: []interface{}{
+				bactype.Destination{
+					ValidDays:      bactype.BitString{true, true, true, true, true, false, false},
+					FromTime:       bactype.Time{Hour: 6},
+					ToTime:         bactype.Time{Hour: 18, Minute: 30},
+					Recipient:      bactype.Recipient{Device: &device},
+					ProcessID:      7,
+					IssueConfirmed: true,
+					Transitions:    bactype.BitString{true, true, true},
+				},
+				bactype.Destination{
+					ValidDays: bactype.BitString{true, true, true, true, true, true, true},
+					ToTime:    bactype.Time{Hour: 23, Minute: 59, Second: 59, Millisecond: 990},
+					Recipient: bactype.Recipient{Address: &bactype.Address{
+						Net: 5,
+						Len: 1,
+						Adr: []uint8{0x1d},
+					}},
+					Transitions: bactype.BitString{true, false, false},
+				},
+			},
+		})
+	})
+
+	t.Run("Date List", func(t *testing.T) {
+		elementTest(t, bactype.ListElement{
+			Object:     bactype.ObjectID{Type: 6, Instance: 1},
+			Property:   23,
+			ArrayIndex: ArrayAll,
+			Elements: []interface{}{
+				bactype.Date{Year: 2018, Month: 12, Day: 25, DayOfWeek: bactype.Tuesday},
+				bactype.DateRange{
+					Start: bactype.Date{Year: 2018, Month: 7, Day: 1, DayOfWeek: bactype.Sunday},
+					End:   bactype.Date{Year: 2018, Month: 7, Day: 14, DayOfWeek: bactype.Saturday},
+				},
+				bactype.WeekNDay{Month: 11, WeekOfMonth: 4, DayOfWeek: bactype.Thursday},
+			},
+		})
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		e := NewEncoder()
+		e.write(bactype.Error)
+		e.write(uint8(2))
+		e.write(bactype.ServiceConfirmedRemoveListElement)
+		e.openingTag(0)
+		e.AppData(bactype.Enumerated(2))
+		e.AppData(bactype.Enumerated(31))
+		e.closingTag(0)
+		e.contextUnsigned(1, 3)
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		var firstFailed uint32
+		if err := NewDecoder(a.RawData).ChangeListError(&firstFailed); err != nil {
+			t.Fatal(err)
+		}
+		if firstFailed != 3 {
+			t.Errorf("First failed element should be 3, got %d", firstFailed)
+		}
+	})
+}
+
+func TestDeviceCommunicationControl(t *testing.T) {
+	reqs := map[string]bactype.DeviceCommunicationControl{
+		"Disable":  {Duration: 60, State: bactype.DisableCommunication, Password: "secret"},
+		"Enable":   {State: bactype.EnableCommunication},
+		"Initiate": {Duration: 5, State: bactype.DisableInitiation},
+	}
+	for name, req := range reqs {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.DeviceCommunicationControl(1, req); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			if a.Service != bactype.ServiceConfirmedDeviceCommunicationControl {
+				t.Fatalf("Service should be device communication control, got %d", a.Service)
+			}
+			var out bactype.DeviceCommunicationControl
+			if err := NewDecoder(a.RawData).DeviceCommunicationControl(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(req, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+			}
+		})
+	}
+
+	e := NewEncoder()
+	err := e.DeviceCommunicationControl(1, bactype.DeviceCommunicationControl{
+		Password: "a password that is too long",
+	})
+	if err == nil {
+		t.Error("A password longer than 20 characters should fail")
+	}
+}
+
+func TestReinitializeDevice(t *testing.T) {
+	reqs := map[string]bactype.ReinitializeDevice{
+		"Password":    {State: bactype.WarmStart, Password: "secret"},
+		"No Password": {State: bactype.ActivateChanges},
+	}
+	for name, req := range reqs {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.ReinitializeDevice(1, req); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			var out bactype.ReinitializeDevice
+			if err := NewDecoder(a.RawData).ReinitializeDevice(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(req, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+			}
+		})
+	}
+}
+
+func TestTimeSync(t *testing.T) {
+	dt := bactype.DateTime{
+		Date: bactype.Date{Year: 2018, Month: 4, Day: 1, DayOfWeek: bactype.Sunday},
+		Time: bactype.Time{Hour: 2, Minute: 59, Second: 59, Millisecond: 500},
+	}
+
+	encoders := map[bactype.ServiceUnconfirmed]func(e *Encoder) error{
+		bactype.ServiceUnconfirmedTimeSync:    func(e *Encoder) error { return e.TimeSync(dt) },
+		bactype.ServiceUnconfirmedUTCTimeSync: func(e *Encoder) error { return e.UTCTimeSync(dt) },
+	}
+	for service, encode := range encoders {
+		e := NewEncoder()
+		if err := encode(e); err != nil {
+			t.Fatal(err)
+		}
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.UnconfirmedService != service {
+			t.Fatalf("Service should be %d, got %d", service, a.UnconfirmedService)
+		}
+
+		var out bactype.DateTime
+		if err := NewDecoder(a.RawData).TimeSync(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(dt, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", dt, out)
+		}
+	}
+}
+
+func TestWhoHas(t *testing.T) {
+	id := bactype.ObjectID{Type: bactype.AnalogInput, Instance: 3}
+	reqs := map[string]bactype.WhoHas{
+		"Name":     {Low: bactype.WhoIsAll, High: bactype.WhoIsAll, Name: "AHU-3 SAT"},
+		"Object":   {Low: 1000, High: 1999, Object: &id},
+		"No Range": {Low: bactype.WhoIsAll, High: bactype.WhoIsAll, Object: &id},
+	}
+	for name, req := range reqs {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.WhoHas(req); err != nil {
+				t.Fatal(err)
+			}
+
+			var a bactype.APDU
+			if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+				t.Fatal(err)
+			}
+			if a.UnconfirmedService != bactype.ServiceUnconfirmedWhoHas {
+				t.Fatalf("Service should be who has, got %d", a.UnconfirmedService)
+			}
+			var out bactype.WhoHas
+			if err := NewDecoder(a.RawData).WhoHas(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(req, out) {
+				t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+			}
+		})
+	}
+}
+
+func TestIHave(t *testing.T) {
+	ih := bactype.IHave{
+		Device: bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234},
+		Object: bactype.ObjectID{Type: bactype.AnalogInput, Instance: 3},
+		Name:   "AHU-3 SAT",
+	}
+	e := NewEncoder()
+	if err := e.IHave(ih); err != nil {
+		t.Fatal(err)
+	}
+
+	var a bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a.UnconfirmedService != bactype.ServiceUnconfirmedIHave {
+		t.Fatalf("Service should be i have, got %d", a.UnconfirmedService)
+	}
+	var out bactype.IHave
+	if err := NewDecoder(a.RawData).IHave(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ih, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ih, out)
+	}
+}
+
+func TestEventNotification(t *testing.T) {
+	flags := bactype.BitString{true, false, false, false}
+	n := bactype.EventNotification{
+		ProcessID:         1,
+		Device:            bactype.ObjectID{Type: bactype.DeviceType, Instance: 10},
+		Object:            bactype.ObjectID{Type: bactype.BinaryInput, Instance: 4},
+		NotificationClass: 2,
+		Priority:          100,
+		MessageText:       "Fan failure",
+		NotifyType:        bactype.NotifyAlarm,
+		AckRequired:       true,
+		FromState:         bactype.EventStateNormal,
+		ToState:           bactype.EventStateOffnormal,
+	}
+
+	notificationTest := func(t *testing.T, n bactype.EventNotification) {
+		e := NewEncoder()
+		if err := e.ConfirmedEventNotification(8, n); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedEventNotification {
+			t.Fatalf("Service should be event notification, got %d", a.Service)
+		}
+		var out bactype.EventNotification
+		if err := NewDecoder(a.RawData).EventNotification(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(n, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", n, out)
+		}
+
+		e = NewEncoder()
+		if err := e.UnconfirmedEventNotification(n); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.UnconfirmedService != bactype.ServiceUnconfirmedEventNotification {
+			t.Fatalf("Service should be event notification, got %d", a.UnconfirmedService)
+		}
+		out = bactype.EventNotification{}
+		if err := NewDecoder(a.RawData).EventNotification(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(n, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", n, out)
+		}
+	}
+
+	n.TimeStamp = bactype.TimeStamp{
+		Type: bactype.TimeStampDateTime,
+		DateTime: bactype.DateTime{
+			Date: bactype.Date{Year: 2018, Month: 5, Day: 2, DayOfWeek: bactype.Wednesday},
+			Time: bactype.Time{Hour: 8, Minute: 15},
+		},
+	}
+	n.EventType = bactype.EventChangeOfState
+	n.EventValues = bactype.ChangeOfStateParameters{
+		NewState:    bactype.PropertyState{Type: 1, Value: 1},
+		StatusFlags: flags,
+	}
+	t.Run("Change of State", func(t *testing.T) { notificationTest(t, n) })
+
+	n.TimeStamp = bactype.TimeStamp{Type: bactype.TimeStampTime, Time: bactype.Time{Hour: 23, Second: 1}}
+	n.EventType = bactype.EventChangeOfBitstring
+	n.EventValues = bactype.ChangeOfBitstringParameters{
+		ReferencedBitstring: bactype.BitString{true, true, false},
+		StatusFlags:         flags,
+	}
+	t.Run("Change of Bitstring", func(t *testing.T) { notificationTest(t, n) })
+
+	n.TimeStamp = bactype.TimeStamp{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 77}
+	n.EventType = bactype.EventChangeOfValue
+	n.EventValues = bactype.ChangeOfValueParameters{NewValue: float32(3.5), StatusFlags: flags}
+	t.Run("Change of Value", func(t *testing.T) { notificationTest(t, n) })
+
+	n.EventType = bactype.EventCommandFailure
+	n.EventValues = bactype.CommandFailureParameters{
+		CommandValue:  uint32(1),
+		StatusFlags:   flags,
+		FeedbackValue: uint32(0),
+	}
+	t.Run("Command Failure", func(t *testing.T) { notificationTest(t, n) })
+
+	n.EventType = bactype.EventFloatingLimit
+	n.EventValues = bactype.FloatingLimitParameters{
+		ReferenceValue: 30,
+		StatusFlags:    flags,
+		SetpointValue:  21,
+		ErrorLimit:     5,
+	}
+	t.Run("Floating Limit", func(t *testing.T) { notificationTest(t, n) })
+
+	n.EventType = bactype.EventUnsignedRange
+	n.EventValues = bactype.UnsignedRangeParameters{
+		ExceedingValue: 1200,
+		StatusFlags:    flags,
+		ExceededLimit:  1000,
+	}
+	t.Run("Unsigned Range", func(t *testing.T) { notificationTest(t, n) })
+
+	n.EventType = bactype.EventBufferReady
+	n.EventValues = bactype.RawParameters{Tag: 10, Data: []byte{0x0e, 0x0c, 0x05, 0x00, 0x00, 0x01, 0x0f, 0x19, 0x02}}
+	t.Run("Raw", func(t *testing.T) { notificationTest(t, n) })
+
+	n.NotifyType = bactype.NotifyAckNotification
+	n.MessageText = ""
+	n.AckRequired = false
+	n.FromState = bactype.EventStateNormal
+	n.EventValues = nil
+	t.Run("Ack Notification", func(t *testing.T) { notificationTest(t, n) })
+}
+
+func TestGetEventInformation(t *testing.T) {
+	last := bactype.ObjectID{Type: bactype.AnalogInput, Instance: 7}
+	for _, req := range []bactype.GetEventInformation{{}, {LastObject: &last}} {
+		e := NewEncoder()
+		if err := e.GetEventInformation(2, req); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedGetEventInformation {
+			t.Fatalf("Service should be get event information, got %d", a.Service)
+		}
+		var out bactype.GetEventInformation
+		if err := NewDecoder(a.RawData).GetEventInformation(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(req, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+		}
+	}
+
+	ack := bactype.GetEventInformationAck{
+		Summaries: []bactype.EventSummary{
+			{
+				Object:                  last,
+				EventState:              bactype.EventStateHighLimit,
+				AcknowledgedTransitions: bactype.BitString{false, true, true},
+				EventTimeStamps: [3]bactype.TimeStamp{
+					{Type: bactype.TimeStampDateTime, DateTime: bactype.DateTime{
+						Date: bactype.Date{Year: 2018, Month: 5, Day: 2, DayOfWeek: bactype.Wednesday},
+						Time: bactype.Time{Hour: 8, Minute: 15},
+					}},
+					{Type: bactype.TimeStampSequenceNumber},
+					{Type: bactype.TimeStampTime, Time: bactype.Time{Hour: 1}},
+				},
+				NotifyType:      bactype.NotifyAlarm,
+				EventEnable:     bactype.BitString{true, true, true},
+				EventPriorities: [3]uint32{100, 100, 200},
+			},
+			{
+				Object:                  bactype.ObjectID{Type: bactype.BinaryInput, Instance: 1},
+				EventState:              bactype.EventStateNormal,
+				AcknowledgedTransitions: bactype.BitString{true, true, false},
+				EventTimeStamps: [3]bactype.TimeStamp{
+					{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 1},
+					{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 2},
+					{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 3},
+				},
+				NotifyType:      bactype.NotifyEvent,
+				EventEnable:     bactype.BitString{true, false, true},
+				EventPriorities: [3]uint32{1, 2, 3},
+			},
+		},
+		MoreEvents: true,
+	}
+	e := NewEncoder()
+	if err := e.GetEventInformationAck(2, ack); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	d := NewDecoder(e.Bytes())
+	if err := d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.GetEventInformationAck
+	if err := d.GetEventInformationAck(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ack, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, out)
+	}
+}
+
+func TestAcknowledgeAlarm(t *testing.T) {
+	ack := bactype.AcknowledgeAlarm{
+		ProcessID:  5,
+		Object:     bactype.ObjectID{Type: bactype.AnalogInput, Instance: 7},
+		EventState: bactype.EventStateHighLimit,
+		TimeStamp:  bactype.TimeStamp{Type: bactype.TimeStampSequenceNumber, SequenceNumber: 42},
+		Source:     "operator",
+		AckTime: bactype.TimeStamp{Type: bactype.TimeStampDateTime, DateTime: bactype.DateTime{
+			Date: bactype.Date{Year: 2018, Month: 5, Day: 3, DayOfWeek: bactype.Thursday},
+			Time: bactype.Time{Hour: 9, Minute: 30, Second: 5},
+		}},
+	}
+	e := NewEncoder()
+	if err := e.AcknowledgeAlarm(6, ack); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Service != bactype.ServiceConfirmedAcknowledgeAlarm {
+		t.Fatalf("Service should be acknowledge alarm, got %d", a.Service)
+	}
+	var out bactype.AcknowledgeAlarm
+	if err := NewDecoder(a.RawData).AcknowledgeAlarm(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ack, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, out)
+	}
+}
+
+func TestAlarmSummary(t *testing.T) {
+	alarms := []bactype.AlarmSummary{
+		{
+			Object:                  bactype.ObjectID{Type: bactype.AnalogInput, Instance: 7},
+			AlarmState:              bactype.EventStateHighLimit,
+			AcknowledgedTransitions: bactype.BitString{false, true, true},
+		},
+		{
+			Object:                  bactype.ObjectID{Type: bactype.BinaryInput, Instance: 1},
+			AlarmState:              bactype.EventStateOffnormal,
+			AcknowledgedTransitions: bactype.BitString{true, true, true},
+		},
+	}
+	e := NewEncoder()
+	if err := e.GetAlarmSummaryAck(1, alarms); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	d := NewDecoder(e.Bytes())
+	if err := d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out []bactype.AlarmSummary
+	if err := d.GetAlarmSummaryAck(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(alarms, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", alarms, out)
+	}
+}
+
+func TestEnrollmentSummary(t *testing.T) {
+	device := bactype.ObjectID{Type: bactype.DeviceType, Instance: 9}
+	state := bactype.EventStateFilterActive
+	eventType := bactype.EventOutOfRange
+	class := uint32(3)
+	for _, req := range []bactype.GetEnrollmentSummary{
+		{AckFilter: bactype.AckFilterNotAcked},
+		{
+			AckFilter:         bactype.AckFilterAll,
+			Recipient:         &bactype.Recipient{Device: &device},
+			ProcessID:         4,
+			EventState:        &state,
+			EventType:         &eventType,
+			Priority:          &bactype.PriorityFilter{Min: 1, Max: 100},
+			NotificationClass: &class,
+		},
+	} {
+		e := NewEncoder()
+		if err := e.GetEnrollmentSummary(1, req); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedGetEnrollmentSummary {
+			t.Fatalf("Service should be get enrollment summary, got %d", a.Service)
+		}
+		var out bactype.GetEnrollmentSummary
+		if err := NewDecoder(a.RawData).GetEnrollmentSummary(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(req, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", req, out)
+		}
+	}
+
+	summaries := []bactype.EnrollmentSummary{
+		{
+			Object:            bactype.ObjectID{Type: bactype.AnalogInput, Instance: 7},
+			EventType:         bactype.EventOutOfRange,
+			EventState:        bactype.EventStateHighLimit,
+			Priority:          100,
+			NotificationClass: &class,
+		},
+		{
+			Object:     bactype.ObjectID{Type: bactype.BinaryInput, Instance: 1},
+			EventType:  bactype.EventChangeOfState,
+			EventState: bactype.EventStateNormal,
+			Priority:   200,
+		},
+	}
+	e := NewEncoder()
+	if err := e.GetEnrollmentSummaryAck(1, summaries); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	d := NewDecoder(e.Bytes())
+	if err := d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out []bactype.EnrollmentSummary
+	if err := d.GetEnrollmentSummaryAck(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(summaries, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", summaries, out)
+	}
+}
+
+func TestPrivateTransfer(t *testing.T) {
+	// The parameters contain a constructed value to check that nested tags
+	// are kept within the parameters.
+	params := NewEncoder()
+	params.AppData(uint32(12))
+	params.openingTag(0)
+	params.AppData("diagnostics")
+	params.closingTag(0)
+
+	for _, pt := range []bactype.PrivateTransfer{
+		{VendorID: 260, ServiceNumber: 1},
+		{VendorID: 7, ServiceNumber: 300, Data: params.Bytes()},
+	} {
+		e := NewEncoder()
+		if err := e.ConfirmedPrivateTransfer(4, pt); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedPrivateTransfer {
+			t.Fatalf("Service should be private transfer, got %d", a.Service)
+		}
+		var out bactype.PrivateTransfer
+		if err := NewDecoder(a.RawData).PrivateTransfer(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pt, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", pt, out)
+		}
+
+		e = NewEncoder()
+		if err := e.UnconfirmedPrivateTransfer(pt); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.UnconfirmedService != bactype.ServiceUnconfirmedPrivateTransfer {
+			t.Fatalf("Service should be private transfer, got %d", a.UnconfirmedService)
+		}
+		out = bactype.PrivateTransfer{}
+		if err := NewDecoder(a.RawData).PrivateTransfer(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pt, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", pt, out)
+		}
+
+		e = NewEncoder()
+		if err := e.PrivateTransferAck(4, pt); err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoder(e.Bytes())
+		if err := d.APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		out = bactype.PrivateTransfer{}
+		if err := d.PrivateTransfer(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pt, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", pt, out)
+		}
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		pt := bactype.PrivateTransfer{VendorID: 7, ServiceNumber: 300, Data: []byte{0x21, 0x05}}
+		e := NewEncoder()
+		e.write(bactype.Error)
+		e.write(uint8(4))
+		e.write(bactype.ServiceConfirmedPrivateTransfer)
+		e.openingTag(0)
+		e.AppData(bactype.Enumerated(5))
+		e.AppData(bactype.Enumerated(0))
+		e.closingTag(0)
+		e.contextUnsigned(1, pt.VendorID)
+		e.contextUnsigned(2, pt.ServiceNumber)
+		e.openingTag(3)
+		e.write(pt.Data)
+		e.closingTag(3)
+
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Error.Class != 5 || a.Error.Code != 0 {
+			t.Fatalf("Error class and code were not decoded properly: %d %d", a.Error.Class, a.Error.Code)
+		}
+		var out bactype.PrivateTransfer
+		if err := NewDecoder(a.RawData).PrivateTransferError(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(pt, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", pt, out)
+		}
+	})
+}
+
+func TestTextMessage(t *testing.T) {
+	source := bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234}
+	for _, msg := range []bactype.TextMessage{
+		{Source: source, Priority: bactype.MessagePriorityNormal, Message: "Filter change due"},
+		{Source: source, Class: uint32(3), Priority: bactype.MessagePriorityUrgent, Message: "Boiler trip"},
+		{Source: source, Class: "maintenance", Priority: bactype.MessagePriorityNormal, Message: ""},
+	} {
+		e := NewEncoder()
+		if err := e.ConfirmedTextMessage(1, msg); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.Service != bactype.ServiceConfirmedTextMessage {
+			t.Fatalf("Service should be text message, got %d", a.Service)
+		}
+		var out bactype.TextMessage
+		if err := NewDecoder(a.RawData).TextMessage(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(msg, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", msg, out)
+		}
+
+		e = NewEncoder()
+		if err := e.UnconfirmedTextMessage(msg); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.UnconfirmedService != bactype.ServiceUnconfirmedTextMessage {
+			t.Fatalf("Service should be text message, got %d", a.UnconfirmedService)
+		}
+		out = bactype.TextMessage{}
+		if err := NewDecoder(a.RawData).TextMessage(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(msg, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", msg, out)
+		}
+	}
+}
+
+func TestWriteGroup(t *testing.T) {
+	inhibit := true
+	for _, wg := range []bactype.WriteGroup{
+		{
+			Group:    100,
+			Priority: 8,
+			Changes: []bactype.GroupChannelValue{
+				{Channel: 1, Value: float32(75)},
+				{Channel: 2, OverridingPriority: 3, Value: nil},
+				{Channel: 300, Value: uint32(1)},
+			},
+		},
+		{Group: 7, Priority: 16, InhibitDelay: &inhibit},
+	} {
+		e := NewEncoder()
+		if err := e.WriteGroup(wg); err != nil {
+			t.Fatal(err)
+		}
+		var a bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if a.UnconfirmedService != bactype.ServiceUnconfirmedWriteGroup {
+			t.Fatalf("Service should be write group, got %d", a.UnconfirmedService)
+		}
+		var out bactype.WriteGroup
+		if err := NewDecoder(a.RawData).WriteGroup(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(wg, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", wg, out)
+		}
+	}
+
+	if err := NewEncoder().WriteGroup(bactype.WriteGroup{Group: 1, Priority: 17}); err == nil {
+		t.Error("Write priority 17 should not be accepted")
+	}
+}
+
+func TestSegmentAck(t *testing.T) {
+	for _, ack := range []bactype.APDU{
+		{DataType: bactype.SegmentAck, InvokeId: 3, Sequence: 7, WindowNumber: 16},
+		{DataType: bactype.SegmentAck, NegativeAck: true, Server: true, InvokeId: 200, Sequence: 255, WindowNumber: 1},
+	} {
+		e := NewEncoder()
+		if err := e.APDU(ack); err != nil {
+			t.Fatal(err)
+		}
+		var out bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ack, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", ack, out)
+		}
+	}
+
+	t.Run("Segmented Complex Ack", func(t *testing.T) {
+		a := bactype.APDU{
+			DataType:         bactype.ComplexAck,
+			SegmentedMessage: true,
+			MoreFollows:      true,
+			InvokeId:         3,
+			Sequence:         4,
+			WindowNumber:     16,
+			Service:          bactype.ServiceConfirmedReadPropMultiple,
+		}
+		e := NewEncoder()
+		if err := e.APDU(a); err != nil {
+			t.Fatal(err)
+		}
+		var out bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", a, out)
+		}
+	})
+
+	t.Run("Segmented Response Accepted", func(t *testing.T) {
+		e := NewEncoder()
+		e.AcceptSegmentedResponse(64)
+		rp := bactype.ReadPropertyData{
+			Object: bactype.Object{
+				ID:         bactype.ObjectID{Type: bactype.DeviceType, Instance: 1},
+				Properties: []bactype.Property{{Type: 76, ArrayIndex: ArrayAll}},
+			},
+		}
+		if err := e.ReadProperty(1, rp); err != nil {
+			t.Fatal(err)
+		}
+		var out bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !out.SegmentedResponseAccepted || out.MaxSegs != 64 {
+			t.Errorf("Request should accept 64 segments, got %t %d", out.SegmentedResponseAccepted, out.MaxSegs)
+		}
+	})
+}
+
+func TestRejectAbort(t *testing.T) {
+	for _, a := range []bactype.APDU{
+		{DataType: bactype.Reject, InvokeId: 12, Reason: uint8(bactype.RejectReasonUnrecognizedService)},
+		{DataType: bactype.Abort, InvokeId: 13, Reason: uint8(bactype.AbortReasonSegmentationNotSupported)},
+		{DataType: bactype.Abort, Server: true, InvokeId: 14, Reason: uint8(bactype.AbortReasonOutOfResources)},
+	} {
+		e := NewEncoder()
+		if err := e.APDU(a); err != nil {
+			t.Fatal(err)
+		}
+		var out bactype.APDU
+		if err := NewDecoder(e.Bytes()).APDU(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, out) {
+			t.Errorf("Encoding/Decoding Failed: %v does not equal %v", a, out)
+		}
+	}
+
+	// Server aborts set the lowest bit of the pdu type
+	e := NewEncoder()
+	e.APDU(bactype.APDU{DataType: bactype.Abort, Server: true, InvokeId: 1, Reason: 4})
+	if b := e.Bytes(); !bytes.Equal(b, []byte{0x71, 1, 4}) {
+		t.Errorf("Abort was encoded as %x", b)
+	}
+}
+
+func TestReadMultiplePropertyError(t *testing.T) {
+	rpm := bactype.ReadMultipleProperty{
+		Objects: []bactype.Object{
+			{
+				ID: bactype.ObjectID{Type: bactype.AnalogInput, Instance: 3},
+				Properties: []bactype.Property{
+					{Type: 77, ArrayIndex: ArrayAll, Data: "Zone Temp"},
+					{Type: 28, ArrayIndex: ArrayAll, Error: &bactype.PropertyError{
+						Class: bactype.ErrorClassProperty,
+						Code:  bactype.ErrorCodeUnknownProperty,
+					}},
+				},
+			},
+			{
+				ID: bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1},
+				Properties: []bactype.Property{
+					{Type: 85, ArrayIndex: ArrayAll, Data: float32(21.5)},
+				},
+			},
+		},
+	}
+	e := NewEncoder()
+	if err := e.ReadMultiplePropertyAck(5, rpm); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDecoder(e.Bytes())
+	var a bactype.APDU
+	if err := d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.ReadMultipleProperty
+	if err := d.ReadMultiplePropertyAck(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rpm, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", rpm, out)
+	}
+}
+
+func TestReadPropertyAckList(t *testing.T) {
+	rp := bactype.ReadPropertyData{
+		Object: bactype.Object{
+			ID: bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234},
+			Properties: []bactype.Property{
+				{
+					Type:       76,
+					ArrayIndex: ArrayAll,
+					Data: []interface{}{
+						bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234},
+						bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1},
+					},
+				},
+			},
+		},
+	}
+	e := NewEncoder()
+	if err := e.ReadPropertyAck(7, rp); err != nil {
+		t.Fatal(err)
+	}
+	b := e.Bytes()
+	if b[len(b)-1] != 0x3F {
+		t.Errorf("Property value must end with closing tag 3, got %x", b[len(b)-1])
+	}
+
+	d := NewDecoder(b)
+	var a bactype.APDU
+	if err := d.APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.ReadPropertyData
+	if err := d.ReadProperty(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rp, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", rp, out)
+	}
+}
+
+func TestReadMultiplePropertyRequest(t *testing.T) {
+	rpm := bactype.ReadMultipleProperty{
+		Objects: []bactype.Object{
+			{
+				ID: bactype.ObjectID{Type: bactype.DeviceType, Instance: bactype.MaxInstance},
+				Properties: []bactype.Property{
+					{Type: 8, ArrayIndex: ArrayAll},
+				},
+			},
+			{
+				ID: bactype.ObjectID{Type: bactype.AnalogInput, Instance: 3},
+				Properties: []bactype.Property{
+					{Type: 85, ArrayIndex: ArrayAll},
+					{Type: 87, ArrayIndex: 16},
+				},
+			},
+		},
+	}
+	e := NewEncoder()
+	if err := e.ReadMultipleProperty(4, rpm); err != nil {
+		t.Fatal(err)
+	}
+	var a bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.ReadMultipleProperty
+	if err := NewDecoder(a.RawData).ReadMultipleProperty(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rpm, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", rpm, out)
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	a := bactype.APDU{
+		DataType: bactype.Error,
+		InvokeId: 9,
+		Service:  bactype.ServiceConfirmedReadProperty,
+	}
+	a.Error.Class = bactype.ErrorClassProperty
+	a.Error.Code = bactype.ErrorCodeUnknownProperty
+
+	e := NewEncoder()
+	if err := e.APDU(a); err != nil {
+		t.Fatal(err)
+	}
+	var out bactype.APDU
+	if err := NewDecoder(e.Bytes()).APDU(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, out) {
+		t.Errorf("Encoding/Decoding Failed: %v does not equal %v", a, out)
+	}
+}
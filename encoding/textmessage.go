@@ -0,0 +1,131 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// ConfirmedTextMessage is a confirmed service request that sends a text
+// message to a device.
+func (e *Encoder) ConfirmedTextMessage(invokeID uint8, data bactype.TextMessage) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedTextMessage,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+	return e.textMessage(data)
+}
+
+// UnconfirmedTextMessage is an unconfirmed service request that sends a text
+// message to one or more devices.
+func (e *Encoder) UnconfirmedTextMessage(data bactype.TextMessage) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedTextMessage,
+	}
+	e.APDU(a)
+	return e.textMessage(data)
+}
+
+func (e *Encoder) textMessage(data bactype.TextMessage) error {
+	// Tag 0 - Source Device
+	e.contextObjectID(0, data.Source.Type, data.Source.Instance)
+
+	// Tag 1 (OPTIONAL) - Message Class
+	if data.Class != nil {
+		e.openingTag(1)
+		switch class := data.Class.(type) {
+		case uint32:
+			e.contextUnsigned(0, class)
+		case string:
+			e.contextString(1, class)
+		default:
+			return fmt.Errorf("message class must be a uint32 or a string, got %T", data.Class)
+		}
+		e.closingTag(1)
+	}
+
+	// Tag 2 - Message Priority
+	e.contextEnumerated(2, uint32(data.Priority))
+
+	// Tag 3 - Message
+	e.contextString(3, data.Message)
+	return e.Error()
+}
+
+// TextMessage decodes the service data of both the confirmed and unconfirmed
+// text message.
+func (d *Decoder) TextMessage(data *bactype.TextMessage) error {
+	var err error
+
+	// Tag 0 - Source Device
+	if data.Source, err = d.contextObjectID(0); err != nil {
+		return err
+	}
+
+	// Tag 1 (OPTIONAL) - Message Class
+	data.Class = nil
+	if d.isOpeningTag(1) {
+		if err = d.openingTag(1); err != nil {
+			return err
+		}
+		if d.isContextTag(0) {
+			data.Class, err = d.contextUnsigned(0)
+		} else {
+			data.Class, err = d.contextString(1)
+		}
+		if err != nil {
+			return err
+		}
+		if err = d.closingTag(1); err != nil {
+			return err
+		}
+	}
+
+	// Tag 2 - Message Priority
+	priority, err := d.contextEnumerated(2)
+	if err != nil {
+		return err
+	}
+	data.Priority = bactype.MessagePriority(priority)
+
+	// Tag 3 - Message
+	data.Message, err = d.contextString(3)
+	return err
+}
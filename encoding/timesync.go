@@ -0,0 +1,64 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// TimeSync is an unconfirmed service request that sets the clock of the
+// receiving devices to the given local date and time.
+func (e *Encoder) TimeSync(dt bactype.DateTime) error {
+	return e.timeSync(bactype.ServiceUnconfirmedTimeSync, dt)
+}
+
+// UTCTimeSync is an unconfirmed service request that sets the clock of the
+// receiving devices to the given UTC date and time.
+func (e *Encoder) UTCTimeSync(dt bactype.DateTime) error {
+	return e.timeSync(bactype.ServiceUnconfirmedUTCTimeSync, dt)
+}
+
+func (e *Encoder) timeSync(service bactype.ServiceUnconfirmed, dt bactype.DateTime) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: service,
+	}
+	e.APDU(a)
+	e.dateTime(dt)
+	return e.Error()
+}
+
+// TimeSync decodes the service data of both the time synchronization and utc
+// time synchronization requests.
+func (d *Decoder) TimeSync(dt *bactype.DateTime) error {
+	return d.dateTime(dt)
+}
@@ -0,0 +1,136 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// WhoHas is an unconfirmed service request to find the devices that contain
+// an object.
+func (e *Encoder) WhoHas(data bactype.WhoHas) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedWhoHas,
+	}
+	e.APDU(a)
+
+	// Tag 0 and 1 (OPTIONAL) - Device Instance Range
+	if data.Low >= 0 && data.High >= 0 && data.Low <= bactype.MaxInstance &&
+		data.High <= bactype.MaxInstance {
+		e.contextUnsigned(0, uint32(data.Low))
+		e.contextUnsigned(1, uint32(data.High))
+	}
+
+	if data.Object != nil {
+		// Tag 2 - Object ID
+		e.contextObjectID(2, data.Object.Type, data.Object.Instance)
+	} else {
+		// Tag 3 - Object Name
+		e.contextString(3, data.Name)
+	}
+	return e.Error()
+}
+
+// WhoHas decodes the service data of a who has request.
+func (d *Decoder) WhoHas(data *bactype.WhoHas) error {
+	var err error
+
+	// Tag 0 and 1 (OPTIONAL) - Device Instance Range
+	data.Low = bactype.WhoIsAll
+	data.High = bactype.WhoIsAll
+	if d.isContextTag(0) {
+		var low, high uint32
+		if low, err = d.contextUnsigned(0); err != nil {
+			return err
+		}
+		if high, err = d.contextUnsigned(1); err != nil {
+			return err
+		}
+		data.Low = int32(low)
+		data.High = int32(high)
+	}
+
+	data.Object = nil
+	data.Name = ""
+	if d.isContextTag(2) {
+		// Tag 2 - Object ID
+		id, err := d.contextObjectID(2)
+		if err != nil {
+			return err
+		}
+		data.Object = &id
+		return nil
+	}
+
+	// Tag 3 - Object Name
+	data.Name, err = d.contextString(3)
+	return err
+}
+
+// IHave is an unconfirmed service request that answers a who has request.
+func (e *Encoder) IHave(data bactype.IHave) error {
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedIHave,
+	}
+	e.APDU(a)
+	e.AppData(data.Device)
+	e.AppData(data.Object)
+	e.AppData(data.Name)
+	return e.Error()
+}
+
+// IHave decodes the service data of an i have request. The address is not
+// part of the service data and is left unchanged.
+func (d *Decoder) IHave(data *bactype.IHave) error {
+	var err error
+	if data.Device, err = d.appObjectID(); err != nil {
+		return err
+	}
+	if data.Object, err = d.appObjectID(); err != nil {
+		return err
+	}
+
+	name, err := d.AppData()
+	if err != nil {
+		return err
+	}
+	s, ok := name.(string)
+	if !ok {
+		return fmt.Errorf("expected object name to be a character string but got %T", name)
+	}
+	data.Name = s
+	return nil
+}
@@ -0,0 +1,147 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// isValidPriority checks that a write priority is within 1 to 16
+func isValidPriority(priority uint8) error {
+	if priority < bactype.MinPriority || priority > bactype.MaxPriority {
+		return fmt.Errorf("priority is %d which must be from %d to %d", priority, bactype.MinPriority, bactype.MaxPriority)
+	}
+	return nil
+}
+
+// WriteGroup is an unconfirmed service request that writes values to the
+// Channel objects of a control group.
+func (e *Encoder) WriteGroup(data bactype.WriteGroup) error {
+	if err := isValidPriority(data.Priority); err != nil {
+		return err
+	}
+
+	a := bactype.APDU{
+		DataType:           bactype.UnconfirmedServiceRequest,
+		UnconfirmedService: bactype.ServiceUnconfirmedWriteGroup,
+	}
+	e.APDU(a)
+
+	// Tag 0 - Group Number
+	e.contextUnsigned(0, data.Group)
+
+	// Tag 1 - Write Priority
+	e.contextUnsigned(1, uint32(data.Priority))
+
+	// Tag 2 - Change List
+	e.openingTag(2)
+	for _, c := range data.Changes {
+		e.contextUnsigned(0, uint32(c.Channel))
+		if c.OverridingPriority != bactype.NoPriority {
+			if err := isValidPriority(c.OverridingPriority); err != nil {
+				return err
+			}
+			e.contextUnsigned(1, uint32(c.OverridingPriority))
+		}
+		if err := e.AppData(c.Value); err != nil {
+			return err
+		}
+	}
+	e.closingTag(2)
+
+	// Tag 3 (OPTIONAL) - Inhibit Delay
+	if data.InhibitDelay != nil {
+		e.contextBoolean(3, *data.InhibitDelay)
+	}
+	return e.Error()
+}
+
+// WriteGroup decodes the service data of a write group request.
+func (d *Decoder) WriteGroup(data *bactype.WriteGroup) error {
+	var err error
+
+	// Tag 0 - Group Number
+	if data.Group, err = d.contextUnsigned(0); err != nil {
+		return err
+	}
+
+	// Tag 1 - Write Priority
+	priority, err := d.contextUnsigned(1)
+	if err != nil {
+		return err
+	}
+	data.Priority = uint8(priority)
+
+	// Tag 2 - Change List
+	if err = d.openingTag(2); err != nil {
+		return err
+	}
+	data.Changes = nil
+	for !d.isClosingTag(2) {
+		if d.len() == 0 {
+			return fmt.Errorf("missing end of change list")
+		}
+		var c bactype.GroupChannelValue
+		channel, err := d.contextUnsigned(0)
+		if err != nil {
+			return err
+		}
+		c.Channel = uint16(channel)
+		if d.isContextTag(1) {
+			priority, err := d.contextUnsigned(1)
+			if err != nil {
+				return err
+			}
+			c.OverridingPriority = uint8(priority)
+		}
+		if c.Value, err = d.AppData(); err != nil {
+			return err
+		}
+		data.Changes = append(data.Changes, c)
+	}
+	if err = d.closingTag(2); err != nil {
+		return err
+	}
+
+	// Tag 3 (OPTIONAL) - Inhibit Delay
+	data.InhibitDelay = nil
+	if d.isContextTag(3) {
+		inhibit, err := d.contextBoolean(3)
+		if err != nil {
+			return err
+		}
+		data.InhibitDelay = &inhibit
+	}
+	return d.Error()
+}
@@ -0,0 +1,217 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// WriteMultipleProperty is a service request to write to multiple properties
+// of multiple objects at once.
+func (e *Encoder) WriteMultipleProperty(invokeID uint8, data bactype.WriteMultipleProperty) error {
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedWritePropMultiple,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	for _, obj := range data.Objects {
+		if err := isValidObjectType(obj.ID.Type); err != nil {
+			return err
+		}
+		// Tag 0 - Object ID
+		e.contextObjectID(0, obj.ID.Type, obj.ID.Instance)
+
+		// Tag 1 - List of Properties
+		e.openingTag(1)
+		if err := e.propertyValues(obj.Properties); err != nil {
+			return err
+		}
+		e.closingTag(1)
+	}
+	return e.Error()
+}
+
+// propertyValues encodes a list of properties along with their values.
+func (e *Encoder) propertyValues(properties []bactype.Property) error {
+	for _, prop := range properties {
+		if err := isValidPropertyType(prop.Type); err != nil {
+			return err
+		}
+		// Tag 0 - Property ID
+		e.contextEnumerated(0, prop.Type)
+
+		// Tag 1 (OPTIONAL) - Array Index
+		if prop.ArrayIndex != ArrayAll {
+			e.contextUnsigned(1, prop.ArrayIndex)
+		}
+
+		// Tag 2 - Value
+		e.openingTag(2)
+		e.propertyValue(prop.Data)
+		e.closingTag(2)
+
+		// Tag 3 (OPTIONAL) - Priority
+		if prop.Priority != bactype.NoPriority {
+			e.contextUnsigned(3, uint32(prop.Priority))
+		}
+	}
+	return e.Error()
+}
+
+// WriteMultipleProperty decodes the service data of a write property multiple
+// request.
+func (d *Decoder) WriteMultipleProperty(data *bactype.WriteMultipleProperty) error {
+	for d.Error() == nil && d.len() > 0 {
+		var obj bactype.Object
+		var err error
+
+		// Tag 0 - Object ID
+		obj.ID, err = d.contextObjectID(0)
+		if err != nil {
+			return err
+		}
+
+		// Tag 1 - List of Properties
+		if err = d.openingTag(1); err != nil {
+			return err
+		}
+		obj.Properties, err = d.propertyValues(1)
+		if err != nil {
+			return err
+		}
+		data.Objects = append(data.Objects, obj)
+	}
+	return d.Error()
+}
+
+// propertyValues decodes a list of properties along with their values until
+// the closing tag with the given tag number is found. The closing tag is
+// consumed.
+func (d *Decoder) propertyValues(closingTag uint8) ([]bactype.Property, error) {
+	props := []bactype.Property{}
+	for !d.isClosingTag(closingTag) {
+		if err := d.Error(); err != nil {
+			return nil, err
+		}
+		if d.len() == 0 {
+			return nil, &ErrorWrongTagType{ClosingTag}
+		}
+
+		var prop bactype.Property
+		var err error
+
+		// Tag 0 - Property ID
+		prop.Type, err = d.contextEnumerated(0)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tag 1 (OPTIONAL) - Array Index
+		prop.ArrayIndex = ArrayAll
+		if d.isContextTag(1) {
+			prop.ArrayIndex, err = d.contextUnsigned(1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Tag 2 - Value
+		if err = d.openingTag(2); err != nil {
+			return nil, err
+		}
+		prop.Data, err = d.propertyValue(2)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tag 3 (OPTIONAL) - Priority
+		if d.isContextTag(3) {
+			priority, err := d.contextUnsigned(3)
+			if err != nil {
+				return nil, err
+			}
+			prop.Priority = uint8(priority)
+		}
+		props = append(props, prop)
+	}
+	return props, d.closingTag(closingTag)
+}
+
+// WriteMultiplePropertyError decodes the first failed write attempt that
+// follows the error class and code of a write property multiple error.
+func (d *Decoder) WriteMultiplePropertyError(ref *bactype.ObjectPropertyReference) error {
+	if err := d.openingTag(1); err != nil {
+		return err
+	}
+	if err := d.objectPropertyReference(ref); err != nil {
+		return err
+	}
+	return d.closingTag(1)
+}
+
+// objectPropertyReference decodes an object property reference
+func (d *Decoder) objectPropertyReference(ref *bactype.ObjectPropertyReference) error {
+	var err error
+
+	// Tag 0 - Object ID
+	ref.Object, err = d.contextObjectID(0)
+	if err != nil {
+		return err
+	}
+
+	// Tag 1 - Property ID
+	ref.Property, err = d.contextEnumerated(1)
+	if err != nil {
+		return err
+	}
+
+	// Tag 2 (OPTIONAL) - Array Index
+	ref.ArrayIndex = ArrayAll
+	if d.isContextTag(2) {
+		ref.ArrayIndex, err = d.contextUnsigned(2)
+	}
+	return err
+}
+
+// objectPropertyReference encodes an object property reference
+func (e *Encoder) objectPropertyReference(ref bactype.ObjectPropertyReference) {
+	e.contextObjectID(0, ref.Object.Type, ref.Object.Instance)
+	e.contextEnumerated(1, ref.Property)
+	if ref.ArrayIndex != ArrayAll {
+		e.contextUnsigned(2, ref.ArrayIndex)
+	}
+}
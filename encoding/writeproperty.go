@@ -0,0 +1,176 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package encoding
+
+import (
+	"fmt"
+
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// WriteProperty is a service request to write a single property. The value
+// stored in the first property of the object is written.
+func (e *Encoder) WriteProperty(invokeID uint8, data bactype.WritePropertyData) error {
+	if len(data.Object.Properties) != 1 {
+		return fmt.Errorf("Property length length must be 1 not %d", len(data.Object.Properties))
+	}
+
+	a := bactype.APDU{
+		DataType:         bactype.ConfirmedServiceRequest,
+		Service:          bactype.ServiceConfirmedWriteProperty,
+		MaxSegs:          0,
+		MaxApdu:          MaxAPDU,
+		InvokeId:         invokeID,
+		SegmentedMessage: false,
+	}
+	e.APDU(a)
+
+	tagID, err := e.readPropertyHeader(initialTagPos, bactype.ReadPropertyData{Object: data.Object})
+	if err != nil {
+		return err
+	}
+
+	// Tag 3 - Property Value
+	e.openingTag(tagID)
+	e.propertyValue(data.Object.Properties[0].Data)
+	e.closingTag(tagID)
+	tagID++
+
+	// Tag 4 (OPTIONAL) - Priority
+	if data.Priority != bactype.NoPriority {
+		e.contextUnsigned(tagID, uint32(data.Priority))
+	}
+	return e.Error()
+}
+
+// propertyValue encodes a value that is to be placed between an opening and
+// closing tag. A slice is encoded as a list of values.
+func (e *Encoder) propertyValue(data interface{}) {
+	if list, ok := data.([]interface{}); ok {
+		for _, v := range list {
+			e.AppData(v)
+		}
+		return
+	}
+	e.AppData(data)
+}
+
+// WriteProperty decodes the service data of a write property request.
+func (d *Decoder) WriteProperty(data *bactype.WritePropertyData) error {
+	var prop bactype.Property
+
+	// Tag 0 - Object ID
+	var expectedTag uint8
+	tag, meta := d.tagNumber()
+	if tag != expectedTag {
+		return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
+	}
+	if !meta.isContextSpecific() {
+		return &ErrorWrongTagType{ContextTag}
+	}
+	objectType, instance := d.objectId()
+	data.Object.ID.Type = objectType
+	data.Object.ID.Instance = instance
+
+	// Tag 1 - Property ID
+	expectedTag++
+	tag, meta, length := d.tagNumberAndValue()
+	if tag != expectedTag {
+		return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
+	}
+	prop.Type = d.enumerated(int(length))
+
+	// Tag 2 - (Optional) Array Index
+	expectedTag++
+	tag, meta, length = d.tagNumberAndValue()
+	if tag == expectedTag && !meta.isOpening() {
+		prop.ArrayIndex = d.unsigned(int(length))
+		tag, meta = d.tagNumber()
+	} else {
+		prop.ArrayIndex = ArrayAll
+	}
+
+	// Tag 3 - Property Value
+	expectedTag = 3
+	if tag != expectedTag {
+		return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
+	}
+	if !meta.isOpening() {
+		return &ErrorWrongTagType{OpeningTag}
+	}
+	value, err := d.propertyValue(expectedTag)
+	if err != nil {
+		return err
+	}
+	prop.Data = value
+
+	// Tag 4 - (Optional) Priority
+	expectedTag++
+	data.Priority = bactype.NoPriority
+	if d.len() > 0 {
+		tag, _, length = d.tagNumberAndValue()
+		if tag != expectedTag {
+			return &ErrorIncorrectTag{Expected: expectedTag, Given: tag}
+		}
+		data.Priority = uint8(d.unsigned(int(length)))
+	}
+
+	data.Object.Properties = []bactype.Property{prop}
+	return d.Error()
+}
+
+// propertyValue decodes all values until the closing tag with the given tag
+// number is found. The closing tag is consumed. If only a single value is found
+// that value is returned, otherwise a slice of all values is returned.
+func (d *Decoder) propertyValue(closingTag uint8) (interface{}, error) {
+	datalist := make([]interface{}, 0)
+	for d.Error() == nil && d.len() > 0 && !d.isClosingTag(closingTag) {
+		data, err := d.AppData()
+		if err != nil {
+			return nil, err
+		}
+		datalist = append(datalist, data)
+	}
+
+	if !d.isClosingTag(closingTag) {
+		if err := d.Error(); err != nil {
+			return nil, err
+		}
+		return nil, &ErrorWrongTagType{ClosingTag}
+	}
+	d.tagNumber()
+
+	if len(datalist) == 1 {
+		return datalist[0], d.Error()
+	}
+	return datalist, d.Error()
+}
@@ -0,0 +1,205 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Error is returned when a device replies to a request with an error, and is
+// the same error that is set on the properties of a read property multiple
+// that could not be read. It can be compared against the errors below with
+// errors.Is, which only looks at the error code since devices do not always
+// agree on the class of an error.
+type Error = bactype.PropertyError
+
+// Common errors returned by devices
+var (
+	ErrUnknownObject     = &Error{Class: bactype.ErrorClassObject, Code: bactype.ErrorCodeUnknownObject}
+	ErrUnknownProperty   = &Error{Class: bactype.ErrorClassProperty, Code: bactype.ErrorCodeUnknownProperty}
+	ErrInvalidArrayIndex = &Error{Class: bactype.ErrorClassProperty, Code: bactype.ErrorCodeInvalidArrayIndex}
+	ErrValueOutOfRange   = &Error{Class: bactype.ErrorClassProperty, Code: bactype.ErrorCodeValueOutOfRange}
+	ErrWriteAccessDenied = &Error{Class: bactype.ErrorClassProperty, Code: bactype.ErrorCodeWriteAccessDenied}
+	ErrPasswordFailure   = &Error{Class: bactype.ErrorClassSecurity, Code: bactype.ErrorCodePasswordFailure}
+)
+
+// Reject is returned when a device could not parse or did not understand a
+// request.
+type Reject struct {
+	Reason bactype.RejectReason
+}
+
+func (e *Reject) Error() string {
+	return fmt.Sprintf("request rejected: %v", e.Reason)
+}
+
+// Abort is returned when a transaction was aborted before it completed. Server
+// is set when the device aborted the transaction.
+type Abort struct {
+	Reason bactype.AbortReason
+	Server bool
+}
+
+func (e *Abort) Error() string {
+	return fmt.Sprintf("transaction aborted: %v", e.Reason)
+}
+
+// WritePropertyMultipleError is returned when one of the writes within a write
+// property multiple request fails. The device stops at the first failed write,
+// so every property before FirstFailed has already been written.
+type WritePropertyMultipleError struct {
+	Class       bactype.ErrorClass
+	Code        bactype.ErrorCode
+	FirstFailed bactype.ObjectPropertyReference
+}
+
+func (e *WritePropertyMultipleError) Error() string {
+	return fmt.Sprintf("Error Class %v Code %v writing property %d of %v", e.Class,
+		e.Code, e.FirstFailed.Property, e.FirstFailed.Object)
+}
+
+// Unwrap returns the error class and code of the failure.
+func (e *WritePropertyMultipleError) Unwrap() error {
+	return &Error{Class: e.Class, Code: e.Code}
+}
+
+// CreateObjectError is returned when a device failed to create an object.
+// FirstFailed is the 1 based position of the initial value that could not be
+// written, or 0 if the failure was not caused by an initial value.
+type CreateObjectError struct {
+	Class       bactype.ErrorClass
+	Code        bactype.ErrorCode
+	FirstFailed uint32
+}
+
+func (e *CreateObjectError) Error() string {
+	if e.FirstFailed == 0 {
+		return fmt.Sprintf("Error Class %v Code %v creating object", e.Class, e.Code)
+	}
+	return fmt.Sprintf("Error Class %v Code %v writing initial value %d", e.Class,
+		e.Code, e.FirstFailed)
+}
+
+// Unwrap returns the error class and code of the failure.
+func (e *CreateObjectError) Unwrap() error {
+	return &Error{Class: e.Class, Code: e.Code}
+}
+
+// ChangeListError is returned when adding elements to, or removing elements
+// from, a list fails. FirstFailed is the 1 based position of the first element
+// that could not be added or removed. The list is left unchanged.
+type ChangeListError struct {
+	Class       bactype.ErrorClass
+	Code        bactype.ErrorCode
+	FirstFailed uint32
+}
+
+func (e *ChangeListError) Error() string {
+	return fmt.Sprintf("Error Class %v Code %v changing list element %d", e.Class,
+		e.Code, e.FirstFailed)
+}
+
+// Unwrap returns the error class and code of the failure.
+func (e *ChangeListError) Unwrap() error {
+	return &Error{Class: e.Class, Code: e.Code}
+}
+
+// PrivateTransferError is returned when a vendor's proprietary service fails.
+// Parameters holds the encoded error parameters of the vendor, if any.
+type PrivateTransferError struct {
+	Class         bactype.ErrorClass
+	Code          bactype.ErrorCode
+	VendorID      uint32
+	ServiceNumber uint32
+	Parameters    []byte
+}
+
+func (e *PrivateTransferError) Error() string {
+	return fmt.Sprintf("Error Class %v Code %v in private transfer service %d of vendor %d",
+		e.Class, e.Code, e.ServiceNumber, e.VendorID)
+}
+
+// Unwrap returns the error class and code of the failure.
+func (e *PrivateTransferError) Unwrap() error {
+	return &Error{Class: e.Class, Code: e.Code}
+}
+
+// apduError converts an error apdu into an error. Services that return
+// additional information along with the error class and code have that
+// information decoded here.
+func apduError(apdu bactype.APDU) error {
+	switch apdu.Service {
+	case bactype.ServiceConfirmedWritePropMultiple:
+		err := &WritePropertyMultipleError{
+			Class: apdu.Error.Class,
+			Code:  apdu.Error.Code,
+		}
+		dec := encoding.NewDecoder(apdu.RawData)
+		if dec.WriteMultiplePropertyError(&err.FirstFailed) == nil {
+			return err
+		}
+	case bactype.ServiceConfirmedCreateObject:
+		err := &CreateObjectError{
+			Class: apdu.Error.Class,
+			Code:  apdu.Error.Code,
+		}
+		dec := encoding.NewDecoder(apdu.RawData)
+		if dec.CreateObjectError(&err.FirstFailed) == nil {
+			return err
+		}
+	case bactype.ServiceConfirmedAddListElement, bactype.ServiceConfirmedRemoveListElement:
+		err := &ChangeListError{
+			Class: apdu.Error.Class,
+			Code:  apdu.Error.Code,
+		}
+		dec := encoding.NewDecoder(apdu.RawData)
+		if dec.ChangeListError(&err.FirstFailed) == nil {
+			return err
+		}
+	case bactype.ServiceConfirmedPrivateTransfer:
+		var pt bactype.PrivateTransfer
+		dec := encoding.NewDecoder(apdu.RawData)
+		if dec.PrivateTransferError(&pt) == nil {
+			return &PrivateTransferError{
+				Class:         apdu.Error.Class,
+				Code:          apdu.Error.Code,
+				VendorID:      pt.VendorID,
+				ServiceNumber: pt.ServiceNumber,
+				Parameters:    pt.Data,
+			}
+		}
+	}
+	return &Error{Class: apdu.Error.Class, Code: apdu.Error.Code}
+}
@@ -0,0 +1,83 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// SubscribeEvents returns a channel that receives every confirmed and
+// unconfirmed event notification sent to the client, such as alarms from the
+// notification classes the client is a recipient of. Confirmed notifications
+// are acknowledged once they have been passed to the subscribers. The channel
+// is closed by UnsubscribeEvents or when the client is closed.
+func (c *Client) SubscribeEvents() <-chan bactype.EventNotification {
+	return c.events.new(nil, nil).data
+}
+
+// UnsubscribeEvents stops delivering event notifications to the channel and
+// closes it.
+func (c *Client) UnsubscribeEvents(ch <-chan bactype.EventNotification) error {
+	sub := c.events.removeChannel(ch)
+	if sub == nil {
+		return fmt.Errorf("channel does not belong to an event subscription")
+	}
+	sub.stop()
+	return nil
+}
+
+// handleEventNotification passes a notification sent by a device to every
+// subscriber.
+func (c *Client) handleEventNotification(b []byte) error {
+	var n bactype.EventNotification
+	dec := encoding.NewDecoder(b)
+	if err := dec.EventNotification(&n); err != nil {
+		return fmt.Errorf("unable to decode event notification: %v", err)
+	}
+
+	for _, sub := range c.events.matching(n) {
+		if !sub.deliver(n) {
+			c.log.Debugf("dropped event notification of %v, the subscriber is not keeping up", n.Object)
+		}
+	}
+	return nil
+}
+
+// closeEvents stops all event subscriptions
+func (c *Client) closeEvents() {
+	for _, sub := range c.events.removeAll() {
+		sub.stop()
+	}
+}
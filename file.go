@@ -0,0 +1,231 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"io"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Space in a file service message that is not file data. A write request has
+// a 4 octet apdu header, the 5 octet file identifier, the opening and closing
+// tags of the access method, a start of up to 5 octets and either the tag of
+// the data or the record count, which take up to 5 octets each.
+const (
+	fileReadOverhead  = 24
+	fileWriteOverhead = 21
+)
+
+// maxFileResume is the number of times a file transfer resumes a chunk that
+// timed out before giving up.
+const maxFileResume = 3
+
+// recordsPerRead is the number of records requested at a time when reading
+// records up to the end of the file.
+const recordsPerRead = 32
+
+// fileChunkSize returns the amount of file data that fits in a message to or
+// from the device.
+func fileChunkSize(dev bactype.Device, overhead uint32) uint32 {
+	max := dev.MaxApdu
+	if max == 0 || max > encoding.MaxAPDU {
+		max = encoding.MaxAPDU
+	}
+	if max <= overhead {
+		return 1
+	}
+	return max - overhead
+}
+
+// ReadFile reads the entire contents of a File object using stream access and
+// writes them to w. The file is read in chunks that fit into the device's max
+// apdu. A chunk that times out is requested again, so the transfer resumes
+// where it stopped. The number of octets written to w is returned.
+func (c *Client) ReadFile(dev bactype.Device, file bactype.ObjectID, w io.Writer) (int64, error) {
+	chunk := fileChunkSize(dev, fileReadOverhead)
+	var n int64
+	for {
+		ack, err := c.atomicReadFile(dev, bactype.AtomicReadFile{
+			File:  file,
+			Start: int32(n),
+			Count: chunk,
+		})
+		if err != nil {
+			return n, err
+		}
+
+		written, err := w.Write(ack.Data)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+		if ack.EndOfFile || len(ack.Data) == 0 {
+			return n, nil
+		}
+	}
+}
+
+// WriteFile writes everything read from r to a File object using stream
+// access, starting at the beginning of the file. The data is sent in chunks
+// that fit into the device's max apdu and a chunk that times out is sent
+// again. The file is not truncated, write the File_Size property to shorten
+// it. The number of octets written to the device is returned.
+func (c *Client) WriteFile(dev bactype.Device, file bactype.ObjectID, r io.Reader) (int64, error) {
+	buf := make([]byte, fileChunkSize(dev, fileWriteOverhead))
+	var n int64
+	for {
+		read, err := io.ReadFull(r, buf)
+		if read > 0 {
+			_, werr := c.atomicWriteFile(dev, bactype.AtomicWriteFile{
+				File:  file,
+				Start: int32(n),
+				Data:  buf[:read],
+			})
+			if werr != nil {
+				return n, werr
+			}
+			n += int64(read)
+		}
+
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return n, nil
+		default:
+			return n, err
+		}
+	}
+}
+
+// ReadFileRecords reads count records of a File object using record access,
+// starting at record start. A count of 0 reads every record up to the end of
+// the file. Records are requested until the count is reached or the end of
+// the file, resuming from the last record received when a request times out.
+func (c *Client) ReadFileRecords(dev bactype.Device, file bactype.ObjectID, start int32, count uint32) ([][]byte, error) {
+	records := make([][]byte, 0, count)
+	for count == 0 || uint32(len(records)) < count {
+		req := bactype.AtomicReadFile{
+			File:   file,
+			Record: true,
+			Start:  start + int32(len(records)),
+			Count:  recordsPerRead,
+		}
+		if count != 0 {
+			req.Count = count - uint32(len(records))
+		}
+
+		ack, err := c.atomicReadFile(dev, req)
+		if err != nil {
+			return records, err
+		}
+		records = append(records, ack.Records...)
+		if ack.EndOfFile || len(ack.Records) == 0 {
+			break
+		}
+	}
+	return records, nil
+}
+
+// WriteFileRecords writes the records to a File object using record access,
+// starting at record start. A start of -1 appends the records to the end of
+// the file. As many records as fit into the device's max apdu are written at
+// a time and a request that times out is sent again.
+func (c *Client) WriteFileRecords(dev bactype.Device, file bactype.ObjectID, start int32, records [][]byte) error {
+	chunk := fileChunkSize(dev, fileWriteOverhead)
+	for len(records) > 0 {
+		// Always send at least one record, a record too large for the device
+		// fails when sending it.
+		// Each record has a tag of up to 5 octets.
+		n := 1
+		size := uint32(len(records[0])) + 5
+		for ; n < len(records); n++ {
+			size += uint32(len(records[n])) + 5
+			if size > chunk {
+				break
+			}
+		}
+
+		ack, err := c.atomicWriteFile(dev, bactype.AtomicWriteFile{
+			File:    file,
+			Record:  true,
+			Start:   start,
+			Records: records[:n],
+		})
+		if err != nil {
+			return err
+		}
+
+		// The ack holds the record the data was written at, which is needed
+		// when appending.
+		start = ack.Start + int32(n)
+		records = records[n:]
+	}
+	return nil
+}
+
+func (c *Client) atomicReadFile(dev bactype.Device, req bactype.AtomicReadFile) (bactype.AtomicReadFileAck, error) {
+	var out bactype.AtomicReadFileAck
+	for attempt := 0; ; attempt++ {
+		dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedAtomicReadFile, func(enc *encoding.Encoder, id uint8) error {
+			return enc.AtomicReadFile(id, req)
+		})
+		if isTimeout(err) && attempt < maxFileResume {
+			c.log.Debugf("Resuming read of %v at %d: %v", req.File, req.Start, err)
+			continue
+		}
+		if err != nil {
+			return out, err
+		}
+		err = dec.AtomicReadFileAck(&out)
+		return out, err
+	}
+}
+
+func (c *Client) atomicWriteFile(dev bactype.Device, req bactype.AtomicWriteFile) (bactype.AtomicWriteFileAck, error) {
+	var out bactype.AtomicWriteFileAck
+	for attempt := 0; ; attempt++ {
+		dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedAtomicWriteFile, func(enc *encoding.Encoder, id uint8) error {
+			return enc.AtomicWriteFile(id, req)
+		})
+		if isTimeout(err) && attempt < maxFileResume {
+			c.log.Debugf("Resuming write of %v at %d: %v", req.File, req.Start, err)
+			continue
+		}
+		if err != nil {
+			return out, err
+		}
+		err = dec.AtomicWriteFileAck(&out)
+		return out, err
+	}
+}
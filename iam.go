@@ -1,24 +1,65 @@
 package gobacnet
 
 import (
+	"fmt"
+
 	"github.com/alexbeltran/gobacnet/encoding"
 	bactype "github.com/alexbeltran/gobacnet/types"
 )
 
-func (c *Client) iAm(dest bactype.Address) error {
-	enc := encoding.NewEncoder()
-	enc.NPDU(
-		bactype.NPDU{
-			Version:               bactype.ProtocolVersion,
-			Destination:           &dest,
-			IsNetworkLayerMessage: false,
-			ExpectingReply:        false,
-			Priority:              bactype.Normal,
-			HopCount:              bactype.DefaultHopCount,
+// IAm announces the local device to the given address. Devices usually
+// announce themselves to bactype.GlobalBroadcast() when they start.
+func (c *Client) IAm(dest bactype.Address) error {
+	if !c.isDevice() {
+		return fmt.Errorf("client has no device instance")
+	}
+	iam := bactype.IAm{
+		ID:           c.device.ID,
+		MaxApdu:      c.device.MaxApdu,
+		Segmentation: c.device.Segmentation,
+		Vendor:       c.device.Vendor,
+	}
+	return c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+		enc.APDU(bactype.APDU{
+			DataType:           bactype.UnconfirmedServiceRequest,
+			UnconfirmedService: bactype.ServiceUnconfirmedIAm,
 		})
+		return enc.IAm(iam)
+	})
+}
+
+// handleWhoIs answers a who is request that includes our device instance. A
+// request sent directly to us is answered directly, while a broadcast request
+// is answered with a broadcast so that every device on the network learns of
+// us. Requests from a remote network are answered with a global broadcast for
+// the router to pass back.
+func (c *Client) handleWhoIs(src bactype.Address, broadcast bool, data []byte) error {
+	if !c.isDevice() {
+		return nil
+	}
+	var low, high int32
+	if err := encoding.NewDecoder(data).WhoIs(&low, &high); err != nil {
+		return err
+	}
+	if !inWhoIsRange(c.device.ID.Instance, low, high) {
+		return nil
+	}
+
+	dest := src
+	if broadcast {
+		dest = bactype.LocalBroadcast()
+		if src.Net != 0 {
+			dest = bactype.GlobalBroadcast()
+		}
+	}
+	return c.IAm(dest)
+}
 
-	//	iams := []bactype.ObjectID{bactype.ObjectID{Instance: 1, Type: 5}}
-	//	enc.IAm(iams)
-	_, err := c.send(dest, enc.Bytes())
-	return err
+// inWhoIsRange returns if the instance is within the range of a who is request.
+// A request without a range includes every device.
+func inWhoIsRange(instance bactype.ObjectInstance, low, high int32) bool {
+	if low == bactype.WhoIsAll || high == bactype.WhoIsAll {
+		return true
+	}
+	return int64(instance) >= int64(low) && int64(instance) <= int64(high)
 }
@@ -32,11 +32,11 @@ License.
 package gobacnet
 
 import (
-	"fmt"
 	"net"
 	"os"
 
 	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/tsm"
 	bactype "github.com/alexbeltran/gobacnet/types"
 )
 
@@ -45,6 +45,11 @@ func (c *Client) Close() {
 	if c.listener == nil {
 		return
 	}
+
+	// Subscriptions are cancelled while we are still able to talk to devices
+	c.closeCOV()
+	c.closeEvents()
+	c.closePrivateTransfers()
 	c.listener.Close()
 	if f, ok := c.log.Out.(*os.File); ok {
 		f.Close()
@@ -85,7 +90,8 @@ func (c *Client) handleMsg(src *net.UDPAddr, b []byte) {
 		}
 		switch apdu.DataType {
 		case bactype.UnconfirmedServiceRequest:
-			if apdu.UnconfirmedService == bactype.ServiceUnconfirmedIAm {
+			switch apdu.UnconfirmedService {
+			case bactype.ServiceUnconfirmedIAm:
 				c.log.Debug("Received IAm Message")
 				dec = encoding.NewDecoder(apdu.RawData)
 				var iam bactype.IAm
@@ -101,32 +107,115 @@ func (c *Client) handleMsg(src *net.UDPAddr, b []byte) {
 					return
 				}
 				c.utsm.Publish(int(iam.ID.Instance), iam)
-			} else if apdu.UnconfirmedService == bactype.ServiceUnconfirmedWhoIs {
-				dec := encoding.NewDecoder(apdu.RawData)
-				var low, high int32
-				dec.WhoIs(&low, &high)
-				// For now we are going to ignore who is request.
-				//log.WithFields(log.Fields{"low": low, "high": high}).Debug("WHO IS Request")
-			} else {
+			case bactype.ServiceUnconfirmedWhoIs:
+				c.log.Debug("Received WhoIs Message")
+				broadcast := header.Function != bactype.BacFuncUnicast
+				err = c.handleWhoIs(replyAddress(src, npdu), broadcast, apdu.RawData)
+				if err != nil {
+					c.log.Error(err)
+				}
+			case bactype.ServiceUnconfirmedIHave:
+				c.log.Debug("Received IHave Message")
+				var ih bactype.IHave
+				err = encoding.NewDecoder(apdu.RawData).IHave(&ih)
+				if err != nil {
+					c.log.Error(err)
+					return
+				}
+				ih.Addr = replyAddress(src, npdu)
+				c.utsm.Publish(int(ih.Device.Instance), ih)
+			case bactype.ServiceUnconfirmedWhoHas:
+				// Who has requests are not answered from the local store.
+			case bactype.ServiceUnconfirmedWriteGroup:
+				// We have no channel objects to write to.
+			case bactype.ServiceUnconfirmedCOVNotification:
+				c.log.Debug("Received COV Notification")
+				if err := c.handleCOVNotification(apdu.RawData); err != nil {
+					c.log.Error(err)
+				}
+			case bactype.ServiceUnconfirmedEventNotification:
+				c.log.Debug("Received Event Notification")
+				if err := c.handleEventNotification(apdu.RawData); err != nil {
+					c.log.Error(err)
+				}
+			case bactype.ServiceUnconfirmedPrivateTransfer:
+				c.log.Debug("Received Private Transfer")
+				if err := c.handlePrivateTransfer(replyAddress(src, npdu), apdu.RawData); err != nil {
+					c.log.Error(err)
+				}
+			case bactype.ServiceUnconfirmedTextMessage:
+				c.log.Debug("Received Text Message")
+				if err := c.handleTextMessage(replyAddress(src, npdu), apdu.RawData); err != nil {
+					c.log.Error(err)
+				}
+			default:
 				c.log.Errorf("Unconfirmed: %d %v", apdu.UnconfirmedService, apdu.RawData)
 			}
-		case bactype.ComplexAck:
-			c.log.Debug("Received Complex Ack")
+		case bactype.SimpleAck:
+			c.log.Debug("Received Simple Ack")
 			err := c.tsm.Send(int(apdu.InvokeId), send)
 			if err != nil {
 				return
 			}
-		case bactype.ConfirmedServiceRequest:
-			c.log.Debug("Received  Confirmed Service Request")
+		case bactype.ComplexAck:
+			if apdu.SegmentedMessage {
+				c.log.Debugf("Received Complex Ack Segment %d", apdu.Sequence)
+				c.handleSegment(replyAddress(src, npdu), apdu, dec.Bytes())
+				break
+			}
+			c.log.Debug("Received Complex Ack")
 			err := c.tsm.Send(int(apdu.InvokeId), send)
 			if err != nil {
 				return
 			}
+		case bactype.SegmentAck:
+			c.log.Debugf("Received Segment Ack %d", apdu.Sequence)
+			if !apdu.Server {
+				// We do not send segmented responses
+				break
+			}
+			err := c.tsm.Ack(int(apdu.InvokeId), tsm.SegmentAck{
+				Sequence:   apdu.Sequence,
+				WindowSize: apdu.WindowNumber,
+				Negative:   apdu.NegativeAck,
+			})
+			if err != nil {
+				c.log.Debugf("unable to pass segment ack to %d: %v", apdu.InvokeId, err)
+			}
+		case bactype.ConfirmedServiceRequest:
+			c.log.Debug("Received Confirmed Service Request")
+			if apdu.SegmentedMessage {
+				// We do not reassemble segmented requests
+				err := c.abort(replyAddress(src, npdu), apdu.InvokeId, bactype.AbortReasonSegmentationNotSupported)
+				if err != nil {
+					c.log.Error(err)
+				}
+				break
+			}
+			c.handleConfirmed(replyAddress(src, npdu), apdu)
 		case bactype.Error:
-			err := fmt.Errorf("Error Class %d Code %d", apdu.Error.Class, apdu.Error.Code)
-			err = c.tsm.Send(int(apdu.InvokeId), err)
+			err := c.tsm.Send(int(apdu.InvokeId), apduError(apdu))
+			if err != nil {
+				c.log.Debugf("unable to send error to %d: %v", apdu.InvokeId, err)
+			}
+		case bactype.Reject:
+			c.log.Debugf("Received Reject %d", apdu.InvokeId)
+			err := c.tsm.Send(int(apdu.InvokeId), &Reject{Reason: bactype.RejectReason(apdu.Reason)})
 			if err != nil {
-				c.log.Debug("unable to send error to %d: %v", apdu.InvokeId, err)
+				c.log.Debugf("unable to send reject to %d: %v", apdu.InvokeId, err)
+			}
+		case bactype.Abort:
+			c.log.Debugf("Received Abort %d", apdu.InvokeId)
+			if !apdu.Server {
+				// Only the devices we send requests to can abort our transactions
+				break
+			}
+			err := c.tsm.Send(int(apdu.InvokeId), &Abort{
+				Reason: bactype.AbortReason(apdu.Reason),
+				Server: apdu.Server,
+			})
+			if err != nil {
+				c.log.Debugf("unable to send abort to %d: %v", apdu.InvokeId, err)
 			}
 		default:
 			// Ignore it
@@ -144,6 +233,72 @@ func (c *Client) handleMsg(src *net.UDPAddr, b []byte) {
 
 }
 
+// handleConfirmed processes confirmed service requests that other devices sent
+// to us.
+func (c *Client) handleConfirmed(src bactype.Address, apdu bactype.APDU) {
+	switch apdu.Service {
+	case bactype.ServiceConfirmedCOVNotification:
+		c.log.Debug("Received Confirmed COV Notification")
+		if err := c.handleCOVNotification(apdu.RawData); err != nil {
+			c.log.Error(err)
+			return
+		}
+		if err := c.simpleAck(src, apdu.InvokeId, apdu.Service); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedEventNotification:
+		c.log.Debug("Received Confirmed Event Notification")
+		if err := c.handleEventNotification(apdu.RawData); err != nil {
+			c.log.Error(err)
+			return
+		}
+		if err := c.simpleAck(src, apdu.InvokeId, apdu.Service); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedTextMessage:
+		c.log.Debug("Received Confirmed Text Message")
+		if err := c.handleTextMessage(src, apdu.RawData); err != nil {
+			c.log.Error(err)
+			return
+		}
+		if err := c.simpleAck(src, apdu.InvokeId, apdu.Service); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedReadProperty:
+		c.log.Debug("Received Read Property")
+		if err := c.handleReadProperty(src, apdu); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedReadPropMultiple:
+		c.log.Debug("Received Read Property Multiple")
+		if err := c.handleReadPropertyMultiple(src, apdu); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedWriteProperty:
+		c.log.Debug("Received Write Property")
+		if err := c.handleWriteProperty(src, apdu); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedSubscribeCOV:
+		c.log.Debug("Received Subscribe COV")
+		if err := c.handleSubscribeCOV(src, apdu); err != nil {
+			c.log.Error(err)
+		}
+	case bactype.ServiceConfirmedSubscribeCOVProperty:
+		c.log.Debug("Received Subscribe COV Property")
+		if err := c.handleSubscribeCOVProperty(src, apdu); err != nil {
+			c.log.Error(err)
+		}
+	default:
+		c.log.Errorf("Confirmed: %d %v", apdu.Service, apdu.RawData)
+		if c.objects != nil {
+			if err := c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService); err != nil {
+				c.log.Error(err)
+			}
+		}
+	}
+}
+
 // listen for incoming bacnet packets.
 func (c *Client) listen() error {
 	var err error = nil
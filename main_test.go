@@ -32,11 +32,18 @@ License.
 package gobacnet
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/alexbeltran/gobacnet/encoding"
 	"github.com/alexbeltran/gobacnet/property"
+	"github.com/alexbeltran/gobacnet/store"
 
 	"github.com/alexbeltran/gobacnet/types"
 )
@@ -142,3 +149,374 @@ func testWhoIs(c *Client, t *testing.T) {
 		t.Fatalf("Unable to find device id %d", testServer)
 	}
 }
+
+func TestSegmentRequest(t *testing.T) {
+	dest := types.Address{Mac: []uint8{192, 168, 1, 2, 0xBA, 0xC0}, MacLen: 6}
+	enc := encoding.NewEncoder()
+	enc.NPDU(types.NPDU{
+		Version:        types.ProtocolVersion,
+		Destination:    &dest,
+		ExpectingReply: true,
+		Priority:       types.Normal,
+		HopCount:       types.DefaultHopCount,
+	})
+	npduLen := len(enc.Bytes())
+	enc.AcceptSegmentedResponse(maxSegments)
+	err := enc.AtomicWriteFile(9, types.AtomicWriteFile{
+		File: types.ObjectID{Type: types.File, Instance: 1},
+		Data: make([]byte, 200),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := enc.Bytes()
+
+	var request types.APDU
+	if err = encoding.NewDecoder(pack[npduLen:]).APDU(&request); err != nil {
+		t.Fatal(err)
+	}
+
+	dev := types.Device{MaxApdu: 50, Segmentation: types.SegmentationBoth}
+	segments, err := segmentRequest(dev, pack[:npduLen], pack[npduLen:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 5 {
+		t.Fatalf("Expected 5 segments, got %d", len(segments))
+	}
+
+	var data []byte
+	for i, seg := range segments {
+		if len(seg)-npduLen > int(dev.MaxApdu) {
+			t.Errorf("Segment %d is larger than the max apdu: %d", i, len(seg)-npduLen)
+		}
+		dec := encoding.NewDecoder(seg)
+		var npdu types.NPDU
+		if err = dec.NPDU(&npdu); err != nil {
+			t.Fatal(err)
+		}
+		var a types.APDU
+		if err = dec.APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		if !a.SegmentedMessage || a.Sequence != uint8(i) || a.MoreFollows != (i < len(segments)-1) {
+			t.Errorf("Segment %d has the wrong header: %+v", i, a)
+		}
+		if a.InvokeId != 9 || a.Service != types.ServiceConfirmedAtomicWriteFile {
+			t.Errorf("Segment %d belongs to the wrong request: %+v", i, a)
+		}
+		data = append(data, a.RawData...)
+	}
+	if !bytes.Equal(data, request.RawData) {
+		t.Errorf("Segments do not add up to the request: %v does not equal %v", data, request.RawData)
+	}
+
+	dev.MaxSegments = 4
+	if _, err = segmentRequest(dev, pack[:npduLen], pack[npduLen:]); err == nil {
+		t.Error("Device accepts 4 segments but the request was split into 5")
+	}
+
+	dev = types.Device{MaxApdu: 50, Segmentation: types.SegmentationTransmit}
+	if _, err = segmentRequest(dev, pack[:npduLen], pack[npduLen:]); err == nil {
+		t.Error("Device does not accept segmented requests but the request was segmented")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	apdu := types.APDU{DataType: types.Error, Service: types.ServiceConfirmedReadProperty}
+	apdu.Error.Class = types.ErrorClassObject
+	apdu.Error.Code = types.ErrorCodeUnknownObject
+	err := apduError(apdu)
+	if !errors.Is(err, ErrUnknownObject) {
+		t.Errorf("%v is not an unknown object error", err)
+	}
+	if errors.Is(err, ErrUnknownProperty) {
+		t.Errorf("%v matches an unknown property error", err)
+	}
+	if s := err.Error(); s != "Error Class object Code unknown-object" {
+		t.Errorf("Unexpected error message %q", s)
+	}
+
+	// Service specific errors wrap the error class and code
+	var wpm error = &WritePropertyMultipleError{
+		Class: types.ErrorClassProperty,
+		Code:  types.ErrorCodeWriteAccessDenied,
+	}
+	if !errors.Is(wpm, ErrWriteAccessDenied) {
+		t.Errorf("%v is not a write access denied error", wpm)
+	}
+	var e *Error
+	if !errors.As(wpm, &e) || e.Class != types.ErrorClassProperty {
+		t.Errorf("Unable to get the error class of %v", wpm)
+	}
+
+	var abort error = &Abort{Reason: types.AbortReasonSegmentationNotSupported, Server: true}
+	if s := abort.Error(); s != "transaction aborted: segmentation-not-supported" {
+		t.Errorf("Unexpected abort message %q", s)
+	}
+	if s := types.ErrorCode(1000).String(); s != "ErrorCode(1000)" {
+		t.Errorf("Unexpected unknown error code %q", s)
+	}
+}
+
+func TestWhoIsRange(t *testing.T) {
+	tests := []struct {
+		low, high int32
+		in        bool
+	}{
+		{types.WhoIsAll, types.WhoIsAll, true},
+		{1000, 2000, true},
+		{1234, 1234, true},
+		{0, 1233, false},
+		{1235, types.MaxInstance, false},
+	}
+	for _, test := range tests {
+		if in := inWhoIsRange(1234, test.low, test.high); in != test.in {
+			t.Errorf("Who is range %d-%d: expected %v, got %v", test.low, test.high, test.in, in)
+		}
+	}
+}
+
+func TestCOVSubscribers(t *testing.T) {
+	dev := types.Device{ID: types.ObjectID{Type: types.DeviceType, Instance: 1234}}
+	objects := store.New(dev)
+	ao := types.ObjectID{Type: types.AnalogOutput, Instance: 1}
+	err := objects.Add(types.Object{
+		ID:   ao,
+		Name: "Damper",
+		Properties: []types.Property{
+			{Type: property.COVIncrement, Data: float32(1)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m covSubscribers
+	now := time.Now()
+	sub := &covSubscriber{
+		processID:  1,
+		object:     ao,
+		property:   property.PresentValue,
+		arrayIndex: types.ArrayAll,
+		expires:    now.Add(time.Minute),
+	}
+	sub.last, _ = sub.values(objects)
+	if err = m.subscribe(sub, now); err != nil {
+		t.Fatal(err)
+	}
+
+	command := func(value float32) []covNotice {
+		objects.WriteProperty(ao, property.PresentValue, types.ArrayAll, value, 8)
+		return m.changed(dev.ID, ao, property.PresentValue, objects, now)
+	}
+	if n := command(0.5); len(n) != 0 {
+		t.Errorf("Change below the cov increment was notified: %v", n)
+	}
+	n := command(1.5)
+	if len(n) != 1 {
+		t.Fatalf("Expected a notification, got %d", len(n))
+	}
+	if n[0].data.TimeRemaining != 60 || n[0].data.Values[0].Data != float32(1.5) {
+		t.Errorf("Unexpected notification %+v", n[0].data)
+	}
+
+	// Status flags are notified regardless of the increment
+	objects.SetProperty(ao, property.OutOfService, true)
+	if n := m.changed(dev.ID, ao, property.StatusFlags, objects, now); len(n) != 1 {
+		t.Errorf("Expected a notification of the status flags, got %d", len(n))
+	}
+
+	// Renewing replaces the subscription, which then expires
+	renewed := *sub
+	renewed.expires = now.Add(time.Second)
+	m.subscribe(&renewed, now)
+	if len(m.subs) != 1 {
+		t.Fatalf("Expected the subscription to be renewed, got %d subscriptions", len(m.subs))
+	}
+	if n := command(5); len(n) != 1 {
+		t.Errorf("Expected a notification, got %d", len(n))
+	}
+	now = now.Add(2 * time.Second)
+	if n := command(10); len(n) != 0 || len(m.subs) != 0 {
+		t.Errorf("Expired subscription was notified")
+	}
+}
+
+func TestSimulateObjects(t *testing.T) {
+	// Devices saved by discover lose the type of their numbers
+	saved := `[{
+		"ID": {"Type": 8, "Instance": 1234},
+		"Objects": {
+			"Device": {"1234": {"Name": "AHU-1", "ID": {"Type": 8, "Instance": 1234}}},
+			"Analog Output": {"1": {"Name": "Damper", "ID": {"Type": 1, "Instance": 1},
+				"Properties": [{"Type": 85, "ArrayIndex": 4294967295, "Data": 42}]}},
+			"Binary Input": {"2": {"Name": "", "ID": {"Type": 3, "Instance": 2}}},
+			"Multi-State Value": {"4": {"Name": "Mode", "ID": {"Type": 19, "Instance": 4},
+				"Properties": [
+					{"Type": 85, "ArrayIndex": 4294967295, "Data": 3},
+					{"Type": 74, "ArrayIndex": 4294967295, "Data": 3},
+					{"Type": 110, "ArrayIndex": 4294967295, "Data": ["Off", "Heat", "Cool"]}
+				]}},
+			"File": {"3": {"Name": "Log", "ID": {"Type": 10, "Instance": 3}}}
+		}
+	}]`
+	var devices []types.Device
+	if err := json.Unmarshal([]byte(saved), &devices); err != nil {
+		t.Fatal(err)
+	}
+
+	s := store.New(devices[0])
+	if errs := simulateObjects(s, devices[0]); len(errs) != 1 {
+		t.Errorf("Expected only the file object to be skipped, got %v", errs)
+	}
+	if objs := s.Objects(); len(objs) != 4 {
+		t.Errorf("Expected 4 objects, got %v", objs)
+	}
+
+	tests := []struct {
+		id    types.ObjectID
+		prop  uint32
+		value interface{}
+	}{
+		{s.Device(), property.ObjectName, "AHU-1"},
+		{types.ObjectID{Type: types.AnalogOutput, Instance: 1}, property.PresentValue, float32(42)},
+		{types.ObjectID{Type: types.AnalogOutput, Instance: 1}, property.RelinquishDefault, float32(42)},
+		{types.ObjectID{Type: types.BinaryInput, Instance: 2}, property.ObjectName, "Binary Input 2"},
+		{types.ObjectID{Type: types.MultiStateValue, Instance: 4}, property.PresentValue, uint32(3)},
+	}
+	for _, test := range tests {
+		value, err := s.ReadProperty(test.id, test.prop, types.ArrayAll)
+		if err != nil || value != test.value {
+			t.Errorf("Expected %s of %v to be %v, got %v (%v)", property.String(test.prop), test.id, test.value, value, err)
+		}
+	}
+	mode := types.ObjectID{Type: types.MultiStateValue, Instance: 4}
+	if text, _ := s.ReadProperty(mode, property.StateText, 2); text != "Heat" {
+		t.Errorf("Expected state text Heat, got %v", text)
+	}
+}
+
+func TestNextRange(t *testing.T) {
+	tests := []struct {
+		name string
+		req  types.ReadRange
+		page types.ReadRangeAck
+		next types.ReadRange
+		ok   bool
+	}{
+		{
+			name: "all continues by position",
+			req:  types.ReadRange{Range: types.RangeAll},
+			page: types.ReadRangeAck{FirstItem: true, MoreItems: true, ItemCount: 10},
+			next: types.ReadRange{Range: types.RangeByPosition, Reference: 11, Count: math.MaxInt32 - 10},
+			ok:   true,
+		},
+		{
+			name: "by position forwards",
+			req:  types.ReadRange{Range: types.RangeByPosition, Reference: 5, Count: 20},
+			page: types.ReadRangeAck{MoreItems: true, ItemCount: 8},
+			next: types.ReadRange{Range: types.RangeByPosition, Reference: 13, Count: 12},
+			ok:   true,
+		},
+		{
+			name: "by position forwards reads the count",
+			req:  types.ReadRange{Range: types.RangeByPosition, Reference: 5, Count: 8},
+			page: types.ReadRangeAck{MoreItems: true, ItemCount: 8},
+			ok:   false,
+		},
+		{
+			name: "by position backwards",
+			req:  types.ReadRange{Range: types.RangeByPosition, Reference: 20, Count: -15},
+			page: types.ReadRangeAck{LastItem: true, MoreItems: true, ItemCount: 5},
+			next: types.ReadRange{Range: types.RangeByPosition, Reference: 15, Count: -10},
+			ok:   true,
+		},
+		{
+			name: "by position backwards reaches the first item",
+			req:  types.ReadRange{Range: types.RangeByPosition, Reference: 5, Count: -15},
+			page: types.ReadRangeAck{FirstItem: true, MoreItems: true, ItemCount: 5},
+			ok:   false,
+		},
+		{
+			name: "by sequence number forwards",
+			req:  types.ReadRange{Range: types.RangeBySequenceNumber, Reference: 100, Count: 50},
+			page: types.ReadRangeAck{MoreItems: true, ItemCount: 20, FirstSequenceNumber: 100},
+			next: types.ReadRange{Range: types.RangeBySequenceNumber, Reference: 120, Count: 30},
+			ok:   true,
+		},
+		{
+			name: "by time continues by sequence number",
+			req:  types.ReadRange{Range: types.RangeByTime, Count: 50},
+			page: types.ReadRangeAck{FirstItem: true, MoreItems: true, ItemCount: 20, FirstSequenceNumber: 7},
+			next: types.ReadRange{Range: types.RangeBySequenceNumber, Reference: 27, Count: 30},
+			ok:   true,
+		},
+		{
+			name: "by time backwards",
+			req:  types.ReadRange{Range: types.RangeByTime, Count: -50},
+			page: types.ReadRangeAck{LastItem: true, MoreItems: true, ItemCount: 20, FirstSequenceNumber: 31},
+			next: types.ReadRange{Range: types.RangeBySequenceNumber, Reference: 30, Count: -30},
+			ok:   true,
+		},
+		{
+			name: "by time backwards reaches sequence number 1",
+			req:  types.ReadRange{Range: types.RangeByTime, Count: -50},
+			page: types.ReadRangeAck{FirstItem: true, MoreItems: true, ItemCount: 20, FirstSequenceNumber: 1},
+			ok:   false,
+		},
+		{
+			name: "by time without a sequence number",
+			req:  types.ReadRange{Range: types.RangeByTime, Count: 50},
+			page: types.ReadRangeAck{MoreItems: true, ItemCount: 20},
+			ok:   false,
+		},
+	}
+	for _, test := range tests {
+		next, ok := nextRange(test.req, test.page)
+		if ok != test.ok {
+			t.Errorf("%s: expected more to read to be %v, got %v", test.name, test.ok, ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(next, test.next) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.next, next)
+		}
+	}
+}
+
+func TestSubscriptionDelivery(t *testing.T) {
+	var m subscriptionManager[uint32]
+	odd := m.new(func(v uint32) bool { return v%2 == 1 }, nil)
+	all := m.new(nil, nil)
+	if odd.id == 0 || odd.id == all.id {
+		t.Fatalf("expected unique non zero ids, got %d and %d", odd.id, all.id)
+	}
+	if subs := m.matching(2); len(subs) != 1 || subs[0] != all {
+		t.Fatalf("expected only the unfiltered subscription to match")
+	}
+
+	// A full buffer drops values instead of blocking the sender
+	for i := 0; i < subscriptionBufferSize; i++ {
+		if !all.deliver(uint32(i)) {
+			t.Fatalf("value %d was dropped before the buffer was full", i)
+		}
+	}
+	if all.deliver(subscriptionBufferSize) {
+		t.Fatal("expected the value to be dropped when the buffer is full")
+	}
+
+	if m.removeChannel(all.data) != all {
+		t.Fatal("expected the subscription of the channel to be removed")
+	}
+	if err := all.cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if !all.deliver(0) {
+		t.Fatal("delivering to a stopped subscription should be ignored")
+	}
+	for range all.data {
+	}
+	if subs := m.removeAll(); len(subs) != 1 || subs[0] != odd {
+		t.Fatalf("expected the remaining subscription to be removed")
+	}
+}
@@ -0,0 +1,77 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// DeviceCommunicationControl enables or disables the communication of a
+// device. Disabling communication silences a device that is flooding the
+// network until the duration has passed, or until communication is enabled
+// again when the duration is 0. The duration is rounded up to whole minutes.
+// Pass an empty password if the device does not require one; if the device
+// rejects the password the error matches ErrPasswordFailure.
+func (c *Client) DeviceCommunicationControl(dev bactype.Device, state bactype.CommunicationState, duration time.Duration, password string) error {
+	minutes := math.Ceil(duration.Minutes())
+	if minutes < 0 || minutes > math.MaxUint16 {
+		return fmt.Errorf("duration must be between 0 and %d minutes, got %v", math.MaxUint16, duration)
+	}
+
+	dcc := bactype.DeviceCommunicationControl{
+		Duration: uint16(minutes),
+		State:    state,
+		Password: password,
+	}
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.DeviceCommunicationControl(id, dcc)
+	})
+}
+
+// ReinitializeDevice restarts a device with a cold or warm start, or starts
+// or ends a backup or restore procedure. Pass an empty password if the device
+// does not require one; if the device rejects the password the error matches
+// ErrPasswordFailure.
+func (c *Client) ReinitializeDevice(dev bactype.Device, state bactype.ReinitializeState, password string) error {
+	rd := bactype.ReinitializeDevice{
+		State:    state,
+		Password: password,
+	}
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ReinitializeDevice(id, rd)
+	})
+}
@@ -0,0 +1,60 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// CreateObject creates an object in the device and returns the identifier of
+// the new object. Set the ID of the request to choose the instance, otherwise
+// the device picks one. If one of the initial values cannot be written, the
+// object is not created and a *CreateObjectError is returned.
+func (c *Client) CreateObject(dev bactype.Device, co bactype.CreateObject) (bactype.ObjectID, error) {
+	var id bactype.ObjectID
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedCreateObject, func(enc *encoding.Encoder, invokeID uint8) error {
+		return enc.CreateObject(invokeID, co)
+	})
+	if err != nil {
+		return id, err
+	}
+	err = dec.CreateObjectAck(&id)
+	return id, err
+}
+
+// DeleteObject deletes an object from the device.
+func (c *Client) DeleteObject(dev bactype.Device, obj bactype.ObjectID) error {
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.DeleteObject(id, obj)
+	})
+}
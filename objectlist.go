@@ -117,7 +117,9 @@ func (c *Client) objectList(dev *bactype.Device) error {
 	return nil
 }
 
-func (c *Client) objectInformation(dev *bactype.Device, objs []bactype.Object) error {
+// objectInformation reads the name and description of the objects, along with
+// their valueProperties when values is set.
+func (c *Client) objectInformation(dev *bactype.Device, objs []bactype.Object, values bool) error {
 	// Often times the map will re arrange the order it spits out
 	// so we need to keep track since the response will be in the
 	// same order we issue the commands.
@@ -133,18 +135,24 @@ func (c *Client) objectInformation(dev *bactype.Device, objs []bactype.Object) e
 		}
 		keys[counter] = o.ID
 		counter++
-		rpm.Objects = append(rpm.Objects, bactype.Object{
-			ID: o.ID,
-			Properties: []bactype.Property{
-				bactype.Property{
-					Type:       property.ObjectName,
-					ArrayIndex: bactype.ArrayAll,
-				},
-				bactype.Property{
-					Type:       property.Description,
-					ArrayIndex: bactype.ArrayAll,
-				},
+		props := []bactype.Property{
+			bactype.Property{
+				Type:       property.ObjectName,
+				ArrayIndex: bactype.ArrayAll,
 			},
+			bactype.Property{
+				Type:       property.Description,
+				ArrayIndex: bactype.ArrayAll,
+			},
+		}
+		if values {
+			for _, p := range valueProperties(o.ID.Type) {
+				props = append(props, bactype.Property{Type: p, ArrayIndex: bactype.ArrayAll})
+			}
+		}
+		rpm.Objects = append(rpm.Objects, bactype.Object{
+			ID:         o.ID,
+			Properties: props,
 		})
 
 	}
@@ -152,33 +160,61 @@ func (c *Client) objectInformation(dev *bactype.Device, objs []bactype.Object) e
 	if err != nil {
 		return fmt.Errorf("unable to read multiple property :%v", err)
 	}
-	var name, description string
-	var ok bool
 	for i, r := range resp.Objects {
-		name, ok = r.Properties[0].Data.(string)
-		if !ok {
-			return fmt.Errorf("expecting string got %T", r.Properties[0].Data)
+		obj := dev.Objects[keys[i].Type][keys[i].Instance]
+		if values {
+			obj.Properties = nil
 		}
-		description, ok = r.Properties[1].Data.(string)
-		if !ok {
-			return fmt.Errorf("expecting string got %T", r.Properties[1].Data)
+		// Description is optional so a device may not have it for every
+		// object. Keep whatever could be read.
+		for _, prop := range r.Properties {
+			if prop.Error != nil {
+				c.log.Debugf("unable to read property %d of %v: %v", prop.Type, keys[i], prop.Error)
+				continue
+			}
+			switch prop.Type {
+			case property.ObjectName, property.Description:
+				s, ok := prop.Data.(string)
+				if !ok {
+					return fmt.Errorf("expecting string got %T", prop.Data)
+				}
+				if prop.Type == property.ObjectName {
+					obj.Name = s
+				} else {
+					obj.Description = s
+				}
+			default:
+				obj.Properties = append(obj.Properties, prop)
+			}
 		}
-		obj := dev.Objects[keys[i].Type][keys[i].Instance]
-		obj.Name = name
-		obj.Description = description
 		dev.Objects[keys[i].Type][keys[i].Instance] = obj
 	}
 	return nil
 }
 
-func (c *Client) allObjectInformation(dev *bactype.Device) error {
+// valueProperties returns the properties that ObjectsWithValues reads along
+// with the name and description of an object, so that the values of the object
+// are known as well.
+func valueProperties(t bactype.ObjectType) []uint32 {
+	switch t {
+	case bactype.AnalogInput, bactype.AnalogOutput, bactype.AnalogValue:
+		return []uint32{property.PresentValue, property.Units}
+	case bactype.BinaryInput, bactype.BinaryOutput, bactype.BinaryValue:
+		return []uint32{property.PresentValue}
+	case bactype.MultiStateInput, bactype.MultiStateOutput, bactype.MultiStateValue:
+		return []uint32{property.PresentValue, property.NumberOfStates, property.StateText}
+	}
+	return nil
+}
+
+func (c *Client) allObjectInformation(dev *bactype.Device, values bool) error {
 	objs := dev.ObjectSlice()
 	incrSize := 5
 
 	var err error
 	for i := 0; i < len(objs); i += incrSize {
 		subset := objs[i:min(i+incrSize, len(objs))]
-		err = c.objectInformation(dev, subset)
+		err = c.objectInformation(dev, subset, values)
 		if err != nil {
 			return err
 		}
@@ -193,11 +229,23 @@ func (c *Client) allObjectInformation(dev *bactype.Device) error {
 // description of the objects. The device returned contains all of the name and
 // description fields for all objects
 func (c *Client) Objects(dev bactype.Device) (bactype.Device, error) {
+	return c.readObjects(dev, false)
+}
+
+// ObjectsWithValues retrieves the objects of the device like Objects does, and
+// also reads the present value, units, number of states and state text of
+// analog, binary and multi-state objects. These are kept in the properties of
+// each object, where the device has them, which is what Simulate serves.
+func (c *Client) ObjectsWithValues(dev bactype.Device) (bactype.Device, error) {
+	return c.readObjects(dev, true)
+}
+
+func (c *Client) readObjects(dev bactype.Device, values bool) (bactype.Device, error) {
 	err := c.objectList(&dev)
 	if err != nil {
 		return dev, fmt.Errorf("unable to get object list: %v", err)
 	}
-	err = c.allObjectInformation(&dev)
+	err = c.allObjectInformation(&dev, values)
 	if err != nil {
 		return dev, fmt.Errorf("unable to get object's information: %v", err)
 	}
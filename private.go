@@ -0,0 +1,228 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// PrivateTransferCodec converts the parameters and results of the proprietary
+// services of a vendor to and from their encoded form, which is the content of
+// the parameters or result block without the enclosing tag. Encoder.AppData
+// and Decoder.AppData of the encoding package can be used for the BACnet
+// values within.
+type PrivateTransferCodec interface {
+	EncodeParameters(serviceNumber uint32, params interface{}) ([]byte, error)
+	DecodeParameters(serviceNumber uint32, data []byte) (interface{}, error)
+	DecodeResult(serviceNumber uint32, data []byte) (interface{}, error)
+}
+
+// PrivateTransferMessage is an unconfirmed private transfer sent to the
+// client. Parameters are decoded by the codec registered for the vendor, or
+// left as the encoded []byte if there is none. They are nil when not sent.
+type PrivateTransferMessage struct {
+	Src           bactype.Address
+	VendorID      uint32
+	ServiceNumber uint32
+	Parameters    interface{}
+}
+
+// privateTransferManager holds the codecs of the vendors and the subscribers
+// of unconfirmed private transfers.
+type privateTransferManager struct {
+	mutex  sync.Mutex
+	codecs map[uint32]PrivateTransferCodec
+	subs   subscriptionManager[PrivateTransferMessage]
+}
+
+func (m *privateTransferManager) register(vendorID uint32, codec PrivateTransferCodec) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.codecs == nil {
+		m.codecs = make(map[uint32]PrivateTransferCodec)
+	}
+	if codec == nil {
+		delete(m.codecs, vendorID)
+		return
+	}
+	m.codecs[vendorID] = codec
+}
+
+func (m *privateTransferManager) codec(vendorID uint32) PrivateTransferCodec {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.codecs[vendorID]
+}
+
+// RegisterPrivateTransferCodec sets the codec used for the private transfers
+// of a vendor. A nil codec removes the codec of the vendor.
+func (c *Client) RegisterPrivateTransferCodec(vendorID uint32, codec PrivateTransferCodec) {
+	c.private.register(vendorID, codec)
+}
+
+// encodeParameters encodes the parameters with the codec of the vendor. Without
+// a codec, the parameters must already be encoded.
+func (c *Client) encodeParameters(vendorID, serviceNumber uint32, params interface{}) ([]byte, error) {
+	if codec := c.private.codec(vendorID); codec != nil {
+		return codec.EncodeParameters(serviceNumber, params)
+	}
+	switch p := params.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return p, nil
+	default:
+		return nil, fmt.Errorf("no codec registered for vendor %d to encode %T", vendorID, params)
+	}
+}
+
+// PrivateTransfer calls the proprietary service of a vendor and returns the
+// result block. The parameters and the result are converted by the codec
+// registered for the vendor. Without a codec, params must be the encoded
+// parameters as a []byte, or nil, and the encoded result is returned. The
+// result is nil if the device did not send one.
+func (c *Client) PrivateTransfer(dev bactype.Device, vendorID, serviceNumber uint32, params interface{}) (interface{}, error) {
+	data, err := c.encodeParameters(vendorID, serviceNumber, params)
+	if err != nil {
+		return nil, err
+	}
+	req := bactype.PrivateTransfer{
+		VendorID:      vendorID,
+		ServiceNumber: serviceNumber,
+		Data:          data,
+	}
+
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedPrivateTransfer, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ConfirmedPrivateTransfer(id, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ack bactype.PrivateTransfer
+	if err = dec.PrivateTransfer(&ack); err != nil {
+		return nil, err
+	}
+	if ack.Data == nil {
+		return nil, nil
+	}
+	if codec := c.private.codec(vendorID); codec != nil {
+		return codec.DecodeResult(serviceNumber, ack.Data)
+	}
+	return ack.Data, nil
+}
+
+// UnconfirmedPrivateTransfer sends a proprietary service of a vendor to dest,
+// which may be a single device or a broadcast address. The parameters are
+// encoded like they are for PrivateTransfer.
+func (c *Client) UnconfirmedPrivateTransfer(dest bactype.Address, vendorID, serviceNumber uint32, params interface{}) error {
+	data, err := c.encodeParameters(vendorID, serviceNumber, params)
+	if err != nil {
+		return err
+	}
+	req := bactype.PrivateTransfer{
+		VendorID:      vendorID,
+		ServiceNumber: serviceNumber,
+		Data:          data,
+	}
+	return c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+		return enc.UnconfirmedPrivateTransfer(req)
+	})
+}
+
+// SubscribePrivateTransfer returns a channel that receives the unconfirmed
+// private transfers of a vendor sent to the client. The channel is closed by
+// UnsubscribePrivateTransfer or when the client is closed.
+func (c *Client) SubscribePrivateTransfer(vendorID uint32) <-chan PrivateTransferMessage {
+	match := func(m PrivateTransferMessage) bool {
+		return m.VendorID == vendorID
+	}
+	return c.private.subs.new(match, nil).data
+}
+
+// UnsubscribePrivateTransfer stops delivering private transfers to the channel
+// and closes it.
+func (c *Client) UnsubscribePrivateTransfer(ch <-chan PrivateTransferMessage) error {
+	sub := c.private.subs.removeChannel(ch)
+	if sub == nil {
+		return fmt.Errorf("channel does not belong to a private transfer subscription")
+	}
+	sub.stop()
+	return nil
+}
+
+// handlePrivateTransfer passes an unconfirmed private transfer to the
+// subscribers of the vendor.
+func (c *Client) handlePrivateTransfer(src bactype.Address, b []byte) error {
+	var pt bactype.PrivateTransfer
+	if err := encoding.NewDecoder(b).PrivateTransfer(&pt); err != nil {
+		return fmt.Errorf("unable to decode private transfer: %v", err)
+	}
+
+	msg := PrivateTransferMessage{
+		Src:           src,
+		VendorID:      pt.VendorID,
+		ServiceNumber: pt.ServiceNumber,
+	}
+	subs := c.private.subs.matching(msg)
+	if len(subs) == 0 {
+		return nil
+	}
+	if pt.Data != nil {
+		msg.Parameters = pt.Data
+		if codec := c.private.codec(pt.VendorID); codec != nil {
+			params, err := codec.DecodeParameters(pt.ServiceNumber, pt.Data)
+			if err != nil {
+				return fmt.Errorf("unable to decode parameters of vendor %d service %d: %v", pt.VendorID, pt.ServiceNumber, err)
+			}
+			msg.Parameters = params
+		}
+	}
+
+	for _, sub := range subs {
+		if !sub.deliver(msg) {
+			c.log.Debugf("dropped private transfer of vendor %d, the subscriber is not keeping up", pt.VendorID)
+		}
+	}
+	return nil
+}
+
+// closePrivateTransfers stops all private transfer subscriptions
+func (c *Client) closePrivateTransfers() {
+	for _, sub := range c.private.subs.removeAll() {
+		sub.stop()
+	}
+}
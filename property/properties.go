@@ -3,17 +3,53 @@ package property
 import "fmt"
 
 const (
-	Description      uint32 = 28
-	FileSize         uint32 = 42
-	FileType         uint32 = 43
-	ModelName        uint32 = 70
-	ObjectIdentifier uint32 = 75
-	ObjectList       uint32 = 76
-	ObjectName       uint32 = 77
-	ObjectReference  uint32 = 78
-	ObjectType       uint32 = 79
-	PresentValue     uint32 = 85
-	Units            uint32 = 117
+	ActiveText                   uint32 = 4
+	All                          uint32 = 8
+	ApduTimeout                  uint32 = 11
+	ApplicationSoftwareVersion   uint32 = 12
+	COVIncrement                 uint32 = 22
+	DatabaseRevision             uint32 = 155
+	DateList                     uint32 = 23
+	Description                  uint32 = 28
+	DeviceAddressBinding         uint32 = 30
+	EventState                   uint32 = 36
+	FileSize                     uint32 = 42
+	FileType                     uint32 = 43
+	FirmwareRevision             uint32 = 44
+	InactiveText                 uint32 = 46
+	LogBuffer                    uint32 = 131
+	MaxApduLengthAccepted        uint32 = 62
+	MaxSegmentsAccepted          uint32 = 167
+	ModelName                    uint32 = 70
+	NumberOfApduRetries          uint32 = 73
+	NumberOfStates               uint32 = 74
+	ObjectIdentifier             uint32 = 75
+	ObjectList                   uint32 = 76
+	ObjectName                   uint32 = 77
+	ObjectReference              uint32 = 78
+	ObjectType                   uint32 = 79
+	Optional                     uint32 = 80
+	OutOfService                 uint32 = 81
+	Polarity                     uint32 = 84
+	PresentValue                 uint32 = 85
+	PriorityArray                uint32 = 87
+	ProtocolObjectTypesSupported uint32 = 96
+	ProtocolRevision             uint32 = 139
+	ProtocolServicesSupported    uint32 = 97
+	ProtocolVersion              uint32 = 98
+	RecipientList                uint32 = 102
+	RecordCount                  uint32 = 141
+	Reliability                  uint32 = 103
+	RelinquishDefault            uint32 = 104
+	Required                     uint32 = 105
+	SegmentationSupported        uint32 = 107
+	StateText                    uint32 = 110
+	StatusFlags                  uint32 = 111
+	SystemStatus                 uint32 = 112
+	TotalRecordCount             uint32 = 145
+	Units                        uint32 = 117
+	VendorIdentifier             uint32 = 120
+	VendorName                   uint32 = 121
 )
 
 const (
@@ -23,31 +59,103 @@ const (
 
 // enumMapping should be treated as read only.
 var enumMapping = map[string]uint32{
-	DescriptionStr:     Description,
-	"FileSize":         FileSize,
-	"FileType":         FileType,
-	"ModelName":        ModelName,
-	"ObjectIdentifier": ObjectIdentifier,
-	"ObjectList":       ObjectList,
-	ObjectNameStr:      ObjectName,
-	"ObjectReference":  ObjectReference,
-	"ObjectType":       ObjectType,
-	"PresentValue":     PresentValue,
-	"Units":            Units,
+	"ActiveText":                   ActiveText,
+	"All":                          All,
+	"ApduTimeout":                  ApduTimeout,
+	"ApplicationSoftwareVersion":   ApplicationSoftwareVersion,
+	"COVIncrement":                 COVIncrement,
+	"DatabaseRevision":             DatabaseRevision,
+	"DateList":                     DateList,
+	DescriptionStr:                 Description,
+	"DeviceAddressBinding":         DeviceAddressBinding,
+	"EventState":                   EventState,
+	"FileSize":                     FileSize,
+	"FileType":                     FileType,
+	"FirmwareRevision":             FirmwareRevision,
+	"InactiveText":                 InactiveText,
+	"LogBuffer":                    LogBuffer,
+	"MaxApduLengthAccepted":        MaxApduLengthAccepted,
+	"MaxSegmentsAccepted":          MaxSegmentsAccepted,
+	"ModelName":                    ModelName,
+	"NumberOfApduRetries":          NumberOfApduRetries,
+	"NumberOfStates":               NumberOfStates,
+	"ObjectIdentifier":             ObjectIdentifier,
+	"ObjectList":                   ObjectList,
+	ObjectNameStr:                  ObjectName,
+	"ObjectReference":              ObjectReference,
+	"ObjectType":                   ObjectType,
+	"Optional":                     Optional,
+	"OutOfService":                 OutOfService,
+	"Polarity":                     Polarity,
+	"PresentValue":                 PresentValue,
+	"PriorityArray":                PriorityArray,
+	"ProtocolObjectTypesSupported": ProtocolObjectTypesSupported,
+	"ProtocolRevision":             ProtocolRevision,
+	"ProtocolServicesSupported":    ProtocolServicesSupported,
+	"ProtocolVersion":              ProtocolVersion,
+	"RecipientList":                RecipientList,
+	"RecordCount":                  RecordCount,
+	"Reliability":                  Reliability,
+	"RelinquishDefault":            RelinquishDefault,
+	"Required":                     Required,
+	"SegmentationSupported":        SegmentationSupported,
+	"StateText":                    StateText,
+	"StatusFlags":                  StatusFlags,
+	"SystemStatus":                 SystemStatus,
+	"TotalRecordCount":             TotalRecordCount,
+	"Units":                        Units,
+	"VendorIdentifier":             VendorIdentifier,
+	"VendorName":                   VendorName,
 }
 
 var strMapping = map[uint32]string{
-	Description:      "Description",
-	FileSize:         "File Size",
-	FileType:         "File Type",
-	ModelName:        "Model Name",
-	ObjectIdentifier: "Object Identifier",
-	ObjectList:       "Object List",
-	ObjectName:       "Object Name",
-	ObjectReference:  "Object Reference",
-	ObjectType:       "Object Type",
-	PresentValue:     "Present Value",
-	Units:            "Units",
+	ActiveText:                   "Active Text",
+	All:                          "All",
+	ApduTimeout:                  "APDU Timeout",
+	ApplicationSoftwareVersion:   "Application Software Version",
+	COVIncrement:                 "COV Increment",
+	DatabaseRevision:             "Database Revision",
+	DateList:                     "Date List",
+	Description:                  "Description",
+	DeviceAddressBinding:         "Device Address Binding",
+	EventState:                   "Event State",
+	FileSize:                     "File Size",
+	FileType:                     "File Type",
+	FirmwareRevision:             "Firmware Revision",
+	InactiveText:                 "Inactive Text",
+	LogBuffer:                    "Log Buffer",
+	MaxApduLengthAccepted:        "Max APDU Length Accepted",
+	MaxSegmentsAccepted:          "Max Segments Accepted",
+	ModelName:                    "Model Name",
+	NumberOfApduRetries:          "Number Of APDU Retries",
+	NumberOfStates:               "Number Of States",
+	ObjectIdentifier:             "Object Identifier",
+	ObjectList:                   "Object List",
+	ObjectName:                   "Object Name",
+	ObjectReference:              "Object Reference",
+	ObjectType:                   "Object Type",
+	Optional:                     "Optional",
+	OutOfService:                 "Out Of Service",
+	Polarity:                     "Polarity",
+	PresentValue:                 "Present Value",
+	PriorityArray:                "Priority Array",
+	ProtocolObjectTypesSupported: "Protocol Object Types Supported",
+	ProtocolRevision:             "Protocol Revision",
+	ProtocolServicesSupported:    "Protocol Services Supported",
+	ProtocolVersion:              "Protocol Version",
+	RecipientList:                "Recipient List",
+	RecordCount:                  "Record Count",
+	Reliability:                  "Reliability",
+	RelinquishDefault:            "Relinquish Default",
+	Required:                     "Required",
+	SegmentationSupported:        "Segmentation Supported",
+	StateText:                    "State Text",
+	StatusFlags:                  "Status Flags",
+	SystemStatus:                 "System Status",
+	TotalRecordCount:             "Total Record Count",
+	Units:                        "Units",
+	VendorIdentifier:             "Vendor Identifier",
+	VendorName:                   "Vendor Name",
 }
 
 // listOfKeys should be treated as read only after init
@@ -32,10 +32,6 @@ License.
 package gobacnet
 
 import (
-	"context"
-	"fmt"
-	"time"
-
 	"github.com/alexbeltran/gobacnet/encoding"
 	bactype "github.com/alexbeltran/gobacnet/types"
 )
@@ -47,85 +43,19 @@ const maxReattempt = 2
 // device, it can also read these properties from multiple objects. This is a
 // good feature to read all present values of every object in the device. This
 // is a batch operation compared to a ReadProperty and should be used in place
-// when reading more than two objects/properties.
+// when reading more than two objects/properties. A property that could not be
+// read does not fail the whole request; instead its Error is set to an *Error.
 func (c *Client) ReadMultiProperty(dev bactype.Device, rp bactype.ReadMultipleProperty) (bactype.ReadMultipleProperty, error) {
 	var out bactype.ReadMultipleProperty
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	id, err := c.tsm.ID(ctx)
-	if err != nil {
-		return out, fmt.Errorf("unable to get transaction id: %v", err)
-	}
-	defer c.tsm.Put(id)
-
-	udp, err := c.localUDPAddress()
-	if err != nil {
-		return out, err
-	}
-	src := bactype.UDPToAddress(udp)
-
-	enc := encoding.NewEncoder()
-	enc.NPDU(bactype.NPDU{
-		Version:               bactype.ProtocolVersion,
-		Destination:           &dev.Addr,
-		Source:                &src,
-		IsNetworkLayerMessage: false,
-		ExpectingReply:        true,
-		Priority:              bactype.Normal,
-		HopCount:              bactype.DefaultHopCount,
+	_, dec, err := c.confirmedRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ReadMultipleProperty(id, rp)
 	})
-	enc.ReadMultipleProperty(uint8(id), rp)
-	if enc.Error() != nil {
-		return out, fmt.Errorf("encoding read multiple property failed: %v", err)
-	}
-
-	pack := enc.Bytes()
-	if dev.MaxApdu < uint32(len(pack)) {
-		return out, fmt.Errorf("read multiple property is too large (max: %d given: %d)", dev.MaxApdu, len(pack))
-	}
-	// the value filled doesn't matter. it just needs to be non nil
-	err = fmt.Errorf("go")
-
-	for count := 0; err != nil && count < maxReattempt; count++ {
-		out, err = c.sendReadMultipleProperty(id, dev, pack)
-		if err == nil {
-			return out, nil
-		}
-	}
-	return out, fmt.Errorf("failed %d tries: %v", maxReattempt, err)
-}
-
-func (c *Client) sendReadMultipleProperty(id int, dev bactype.Device, request []byte) (bactype.ReadMultipleProperty, error) {
-	var out bactype.ReadMultipleProperty
-	_, err := c.send(dev.Addr, request)
 	if err != nil {
 		return out, err
 	}
-
-	raw, err := c.tsm.Receive(id, time.Duration(5)*time.Second)
-	if err != nil {
-		return out, fmt.Errorf("unable to receive id %d: %v", id, err)
-	}
-
-	var b []byte
-	switch v := raw.(type) {
-	case error:
-		return out, err
-	case []byte:
-		b = v
-	default:
-		return out, fmt.Errorf("received unknown datatype %T", raw)
-	}
-
-	dec := encoding.NewDecoder(b)
-
-	var apdu bactype.APDU
-	dec.APDU(&apdu)
-	err = dec.ReadMultiplePropertyAck(&out)
-	if err != nil {
-		c.log.Debugf("WEIRD PACKET: %v: %v", err, b)
+	if err = dec.ReadMultiplePropertyAck(&out); err != nil {
+		c.log.Debugf("unable to decode read multiple property ack: %v", err)
 		return out, err
 	}
-	return out, err
+	return out, nil
 }
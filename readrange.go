@@ -0,0 +1,148 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"math"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// ReadRange reads a range of items from a list property, such as the
+// Log_Buffer of a Trend Log or Event Log. Devices may return fewer items than
+// requested when the reply would not fit into a single message. In that case
+// the following items are requested until the entire range has been read.
+func (c *Client) ReadRange(dev bactype.Device, rr bactype.ReadRange) (bactype.ReadRangeAck, error) {
+	out, err := c.readRange(dev, rr)
+	if err != nil {
+		return out, err
+	}
+
+	req := rr
+	page := out
+	for page.MoreItems && page.ItemCount > 0 {
+		var ok bool
+		req, ok = nextRange(req, page)
+		if !ok {
+			break
+		}
+
+		page, err = c.readRange(dev, req)
+		if err != nil {
+			return out, err
+		}
+
+		if req.Count < 0 {
+			// Reading backwards, each page comes before the previous one
+			out.Items = append(page.Items, out.Items...)
+			out.FirstItem = page.FirstItem
+			out.FirstSequenceNumber = page.FirstSequenceNumber
+		} else {
+			out.Items = append(out.Items, page.Items...)
+			out.LastItem = page.LastItem
+		}
+		out.MoreItems = page.MoreItems
+	}
+	out.ItemCount = uint32(len(out.Items))
+	return out, nil
+}
+
+// nextRange returns the request for the items following the given page. False
+// is returned if the requested range has been read.
+func nextRange(req bactype.ReadRange, page bactype.ReadRangeAck) (bactype.ReadRange, bool) {
+	next := req
+	count := int64(req.Count)
+	n := int64(page.ItemCount)
+
+	if req.Range == bactype.RangeAll {
+		// Continue reading the rest of the list by position
+		next.Range = bactype.RangeByPosition
+		next.Reference = 1
+		count = math.MaxInt32
+	}
+
+	// Remove the items that have been read from the count
+	if count < 0 {
+		count += n
+	} else {
+		count -= n
+	}
+	if count == 0 {
+		return next, false
+	}
+	next.Count = int32(count)
+
+	switch next.Range {
+	case bactype.RangeByPosition:
+		if count < 0 {
+			if int64(next.Reference) <= n {
+				return next, false
+			}
+			next.Reference -= uint32(n)
+		} else {
+			next.Reference += uint32(n)
+		}
+	case bactype.RangeBySequenceNumber, bactype.RangeByTime:
+		// Items read by time are continued by their sequence number, which
+		// starts at 1. A sequence number of 0 means the device did not send it.
+		next.Range = bactype.RangeBySequenceNumber
+		if page.FirstSequenceNumber == 0 {
+			return next, false
+		}
+		if count < 0 {
+			if page.FirstSequenceNumber == 1 {
+				return next, false
+			}
+			next.Reference = page.FirstSequenceNumber - 1
+		} else {
+			next.Reference = page.FirstSequenceNumber + uint32(n)
+		}
+	}
+	return next, true
+}
+
+func (c *Client) readRange(dev bactype.Device, rr bactype.ReadRange) (bactype.ReadRangeAck, error) {
+	var out bactype.ReadRangeAck
+
+	dec, err := c.complexAckRequest(dev, bactype.ServiceConfirmedReadRange, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ReadRange(id, rr)
+	})
+	if err != nil {
+		return out, err
+	}
+
+	if err = dec.ReadRangeAck(&out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
@@ -32,80 +32,19 @@ License.
 package gobacnet
 
 import (
-	"context"
-	"fmt"
-	"log"
-	"time"
-
 	"github.com/alexbeltran/gobacnet/encoding"
 	bactype "github.com/alexbeltran/gobacnet/types"
 )
 
 // ReadProperty reads a single property from a single object in the given device.
 func (c *Client) ReadProperty(dest bactype.Device, rp bactype.ReadPropertyData) (bactype.ReadPropertyData, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	id, err := c.tsm.ID(ctx)
-	if err != nil {
-		return bactype.ReadPropertyData{}, fmt.Errorf("unable to get an transaction id: %v", err)
-	}
-	defer c.tsm.Put(id)
-
-	udp, err := c.localUDPAddress()
-	if err != nil {
-		return bactype.ReadPropertyData{}, err
-	}
-	src := bactype.UDPToAddress(udp)
-
-	enc := encoding.NewEncoder()
-	enc.NPDU(bactype.NPDU{
-		Version:               bactype.ProtocolVersion,
-		Destination:           &dest.Addr,
-		Source:                &src,
-		IsNetworkLayerMessage: false,
-		ExpectingReply:        true,
-		Priority:              bactype.Normal,
-		HopCount:              bactype.DefaultHopCount,
+	var out bactype.ReadPropertyData
+	_, dec, err := c.confirmedRequest(dest, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ReadProperty(id, rp)
 	})
-
-	enc.ReadProperty(uint8(id), rp)
-	if enc.Error() != nil {
-		return bactype.ReadPropertyData{}, err
-	}
-
-	// the value filled doesn't matter. it just needs to be non nil
-	err = fmt.Errorf("go")
-	for count := 0; err != nil && count < 2; count++ {
-		var b []byte
-		var out bactype.ReadPropertyData
-		_, err = c.send(dest.Addr, enc.Bytes())
-		if err != nil {
-			log.Print(err)
-			continue
-		}
-
-		raw, err := c.tsm.Receive(id, time.Duration(5)*time.Second)
-		if err != nil {
-			continue
-		}
-		switch v := raw.(type) {
-		case error:
-			return out, err
-		case []byte:
-			b = v
-		default:
-			return out, fmt.Errorf("received unknown datatype %T", raw)
-		}
-
-		dec := encoding.NewDecoder(b)
-
-		var apdu bactype.APDU
-		dec.APDU(&apdu)
-		dec.ReadProperty(&out)
-		if err = dec.Error(); err != nil {
-			continue
-		}
+	if err != nil {
 		return out, err
 	}
-	return bactype.ReadPropertyData{}, err
+	err = dec.ReadProperty(&out)
+	return out, err
 }
@@ -36,6 +36,7 @@ import (
 	"net"
 
 	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/tsm"
 	bactype "github.com/alexbeltran/gobacnet/types"
 )
 
@@ -91,12 +92,168 @@ func (c *Client) send(dest bactype.Address, data []byte) (int, error) {
 		return 0, err
 	}
 
-	// Get IP Address
+	// Get IP Address. Broadcasts may leave out the mac address in which case
+	// the broadcast address of the interface is used.
 	d, err := dest.UDPAddr()
 	if err != nil {
-		return 0, err
+		d, err = c.address(dest)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	// use default udp type, src = local address (nil)
 	return c.listener.WriteTo(e.Bytes(), &d)
 }
+
+// replyAddress returns the address used to respond to the sender of a
+// message. If the message was routed to us, the reply is sent back through the
+// same router.
+func replyAddress(src *net.UDPAddr, npdu bactype.NPDU) bactype.Address {
+	udp := net.UDPAddr{
+		IP:   src.IP.To4(),
+		Port: src.Port,
+	}
+	addr := bactype.UDPToAddress(&udp)
+	if npdu.Source != nil && npdu.Source.Net != 0 {
+		addr.Net = npdu.Source.Net
+		addr.Len = npdu.Source.Len
+		addr.Adr = npdu.Source.Adr
+	}
+	return addr
+}
+
+// segmentAck acknowledges the segments of a segmented response that were
+// received so far.
+func (c *Client) segmentAck(dest bactype.Address, invokeID uint8, ack tsm.SegmentAck) error {
+	enc := encoding.NewEncoder()
+	enc.NPDU(bactype.NPDU{
+		Version:               bactype.ProtocolVersion,
+		Destination:           &dest,
+		IsNetworkLayerMessage: false,
+		ExpectingReply:        false,
+		Priority:              bactype.Normal,
+		HopCount:              bactype.DefaultHopCount,
+	})
+	enc.APDU(bactype.APDU{
+		DataType:     bactype.SegmentAck,
+		NegativeAck:  ack.Negative,
+		InvokeId:     invokeID,
+		Sequence:     ack.Sequence,
+		WindowNumber: ack.WindowSize,
+	})
+	if err := enc.Error(); err != nil {
+		return err
+	}
+	_, err := c.send(dest, enc.Bytes())
+	return err
+}
+
+// handleSegment adds a segment of a segmented complex ack to its transaction.
+// Segments are acknowledged as the sender requires and once the last segment
+// arrives, the complete ack is passed on as if it was sent in one piece. A
+// response with more segments than we accept is aborted.
+func (c *Client) handleSegment(src bactype.Address, apdu bactype.APDU, data []byte) {
+	ack, complete, err := c.tsm.Segment(int(apdu.InvokeId), tsm.Segment{
+		Sequence:    apdu.Sequence,
+		WindowSize:  apdu.WindowNumber,
+		MoreFollows: apdu.MoreFollows,
+		Data:        data,
+	}, maxSegments)
+	if err == tsm.ErrTooManySegments {
+		abort := &Abort{Reason: bactype.AbortReasonBufferOverflow}
+		if err = c.reply(src, bactype.APDU{
+			DataType: bactype.Abort,
+			InvokeId: apdu.InvokeId,
+			Reason:   uint8(abort.Reason),
+		}); err != nil {
+			c.log.Error(err)
+		}
+		if err = c.tsm.Send(int(apdu.InvokeId), abort); err != nil {
+			c.log.Debugf("unable to send abort to %d: %v", apdu.InvokeId, err)
+		}
+		return
+	}
+	if err != nil {
+		c.log.Debugf("Dropped segment %d of id %d: %v", apdu.Sequence, apdu.InvokeId, err)
+		return
+	}
+
+	if ack != nil {
+		if err = c.segmentAck(src, apdu.InvokeId, *ack); err != nil {
+			c.log.Error(err)
+		}
+	}
+	if complete == nil {
+		return
+	}
+
+	enc := encoding.NewEncoder()
+	enc.APDU(bactype.APDU{
+		DataType: bactype.ComplexAck,
+		InvokeId: apdu.InvokeId,
+		Service:  apdu.Service,
+	})
+	if err = enc.Error(); err != nil {
+		c.log.Error(err)
+		return
+	}
+	b := append(enc.Bytes(), complete...)
+	if err = c.tsm.Send(int(apdu.InvokeId), b); err != nil {
+		c.log.Debugf("unable to send reassembled ack to %d: %v", apdu.InvokeId, err)
+	}
+}
+
+// simpleAck acknowledges a confirmed service request that was sent to us.
+func (c *Client) simpleAck(dest bactype.Address, invokeID uint8, service bactype.ServiceConfirmed) error {
+	return c.reply(dest, bactype.APDU{
+		DataType: bactype.SimpleAck,
+		InvokeId: invokeID,
+		Service:  service,
+	})
+}
+
+// reply sends an apdu without any service data, such as a simple ack, error,
+// reject or abort, to the device that made a request.
+func (c *Client) reply(dest bactype.Address, apdu bactype.APDU) error {
+	enc := encoding.NewEncoder()
+	enc.NPDU(bactype.NPDU{
+		Version:               bactype.ProtocolVersion,
+		Destination:           &dest,
+		IsNetworkLayerMessage: false,
+		ExpectingReply:        false,
+		Priority:              bactype.Normal,
+		HopCount:              bactype.DefaultHopCount,
+	})
+	enc.APDU(apdu)
+	if err := enc.Error(); err != nil {
+		return err
+	}
+	_, err := c.send(dest, enc.Bytes())
+	return err
+}
+
+// sendUnconfirmed sends the unconfirmed service request built by service to
+// dest, which may be a single device or a broadcast address.
+func (c *Client) sendUnconfirmed(dest bactype.Address, service func(enc *encoding.Encoder) error) error {
+	src, err := c.localAddress()
+	if err != nil {
+		return err
+	}
+
+	enc := encoding.NewEncoder()
+	enc.NPDU(bactype.NPDU{
+		Version:               bactype.ProtocolVersion,
+		Destination:           &dest,
+		Source:                &src,
+		IsNetworkLayerMessage: false,
+		ExpectingReply:        false,
+		Priority:              bactype.Normal,
+		HopCount:              bactype.DefaultHopCount,
+	})
+	if err = service(enc); err != nil {
+		return err
+	}
+	_, err = c.send(dest, enc.Bytes())
+	return err
+}
@@ -0,0 +1,242 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/property"
+	"github.com/alexbeltran/gobacnet/store"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Store returns the objects served by the local device. Objects added to the
+// store can be read by other devices. Store is nil when the client does not act
+// as a device.
+func (c *Client) Store() *store.Store {
+	return c.objects
+}
+
+// localObject replaces the wildcard device instance, which refers to whichever
+// device receives the request, with our own device.
+func (c *Client) localObject(id bactype.ObjectID) bactype.ObjectID {
+	if id.Type == bactype.DeviceType && id.Instance == bactype.MaxInstance {
+		return c.device.ID
+	}
+	return id
+}
+
+// handleReadProperty answers a read property request from the store.
+func (c *Client) handleReadProperty(src bactype.Address, apdu bactype.APDU) error {
+	if c.objects == nil {
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService)
+	}
+	var rp bactype.ReadPropertyData
+	if err := encoding.NewDecoder(apdu.RawData).ReadProperty(&rp); err != nil {
+		c.log.Debugf("unable to decode read property request: %v", err)
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonInvalidTag)
+	}
+
+	rp.Object.ID = c.localObject(rp.Object.ID)
+	prop := &rp.Object.Properties[0]
+	value, err := c.objects.ReadProperty(rp.Object.ID, prop.Type, prop.ArrayIndex)
+	if err != nil {
+		return c.errorReply(src, apdu, err)
+	}
+	prop.Data = value
+	return c.complexAck(src, apdu, func(enc *encoding.Encoder) error {
+		return enc.ReadPropertyAck(apdu.InvokeId, rp)
+	})
+}
+
+// handleReadPropertyMultiple answers a read property multiple request from the
+// store. Properties that cannot be read are answered with an error in place of
+// their value.
+func (c *Client) handleReadPropertyMultiple(src bactype.Address, apdu bactype.APDU) error {
+	if c.objects == nil {
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService)
+	}
+	var rpm bactype.ReadMultipleProperty
+	if err := encoding.NewDecoder(apdu.RawData).ReadMultipleProperty(&rpm); err != nil {
+		c.log.Debugf("unable to decode read property multiple request: %v", err)
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonInvalidTag)
+	}
+
+	out := bactype.ReadMultipleProperty{
+		Objects: make([]bactype.Object, len(rpm.Objects)),
+	}
+	for i, obj := range rpm.Objects {
+		out.Objects[i] = c.readProperties(c.localObject(obj.ID), obj.Properties)
+	}
+	return c.complexAck(src, apdu, func(enc *encoding.Encoder) error {
+		return enc.ReadMultiplePropertyAck(apdu.InvokeId, out)
+	})
+}
+
+// readProperties reads the properties of an object in the store. Reading the
+// properties all, required or optional returns every property of the object
+// of that kind.
+func (c *Client) readProperties(id bactype.ObjectID, props []bactype.Property) bactype.Object {
+	obj := bactype.Object{ID: id}
+	for _, prop := range props {
+		var list func(bactype.ObjectID) ([]uint32, error)
+		switch prop.Type {
+		case property.All:
+			list = c.objects.Properties
+		case property.Required:
+			list = c.objects.RequiredProperties
+		case property.Optional:
+			list = c.objects.OptionalProperties
+		default:
+			obj.Properties = append(obj.Properties, c.readProperty(id, prop))
+			continue
+		}
+
+		all, err := list(id)
+		if err != nil {
+			prop.Error = propertyError(err)
+			obj.Properties = append(obj.Properties, prop)
+			continue
+		}
+		for _, p := range all {
+			obj.Properties = append(obj.Properties, c.readProperty(id, bactype.Property{
+				Type:       p,
+				ArrayIndex: bactype.ArrayAll,
+			}))
+		}
+	}
+	return obj
+}
+
+func (c *Client) readProperty(id bactype.ObjectID, prop bactype.Property) bactype.Property {
+	value, err := c.objects.ReadProperty(id, prop.Type, prop.ArrayIndex)
+	if err != nil {
+		prop.Error = propertyError(err)
+		return prop
+	}
+	prop.Data = value
+	return prop
+}
+
+// handleWriteProperty writes a property of an object in the store. Writes to
+// the present value of commandable objects are made at the requested priority.
+func (c *Client) handleWriteProperty(src bactype.Address, apdu bactype.APDU) error {
+	if c.objects == nil {
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonUnrecognizedService)
+	}
+	var wp bactype.WritePropertyData
+	if err := encoding.NewDecoder(apdu.RawData).WriteProperty(&wp); err != nil {
+		c.log.Debugf("unable to decode write property request: %v", err)
+		return c.reject(src, apdu.InvokeId, bactype.RejectReasonInvalidTag)
+	}
+
+	id := c.localObject(wp.Object.ID)
+	prop := wp.Object.Properties[0]
+	err := c.objects.WriteProperty(id, prop.Type, prop.ArrayIndex, prop.Data, wp.Priority)
+	if err != nil {
+		return c.errorReply(src, apdu, err)
+	}
+	return c.simpleAck(src, apdu.InvokeId, apdu.Service)
+}
+
+// complexAck sends the reply built by service to a confirmed request. We do not
+// segment replies, so replies that are larger than the requesting device
+// accepts are aborted instead.
+func (c *Client) complexAck(dest bactype.Address, request bactype.APDU, service func(enc *encoding.Encoder) error) error {
+	enc := encoding.NewEncoder()
+	enc.NPDU(bactype.NPDU{
+		Version:               bactype.ProtocolVersion,
+		Destination:           &dest,
+		IsNetworkLayerMessage: false,
+		ExpectingReply:        false,
+		Priority:              bactype.Normal,
+		HopCount:              bactype.DefaultHopCount,
+	})
+	npduLen := len(enc.Bytes())
+	if err := service(enc); err != nil {
+		return err
+	}
+
+	pack := enc.Bytes()
+	max := uint(c.device.MaxApdu)
+	if request.MaxApdu != 0 && request.MaxApdu < max {
+		max = request.MaxApdu
+	}
+	if uint(len(pack)-npduLen) > max {
+		return c.abort(dest, request.InvokeId, bactype.AbortReasonSegmentationNotSupported)
+	}
+	_, err := c.send(dest, pack)
+	return err
+}
+
+// errorReply answers a confirmed request with the error class and code of err.
+// Errors that do not come from the store are reported as other service errors.
+func (c *Client) errorReply(dest bactype.Address, request bactype.APDU, err error) error {
+	apdu := bactype.APDU{
+		DataType: bactype.Error,
+		InvokeId: request.InvokeId,
+		Service:  request.Service,
+	}
+	perr := propertyError(err)
+	apdu.Error.Class = perr.Class
+	apdu.Error.Code = perr.Code
+	return c.reply(dest, apdu)
+}
+
+// propertyError returns the error class and code err is answered with. The
+// store fails with a *bactype.PropertyError, anything else is answered as an
+// unexpected failure of the service.
+func propertyError(err error) *bactype.PropertyError {
+	if perr, ok := err.(*bactype.PropertyError); ok {
+		return perr
+	}
+	return &bactype.PropertyError{Class: bactype.ErrorClassServices, Code: bactype.ErrorCodeOther}
+}
+
+// reject answers a confirmed request that we could not parse or do not
+// execute.
+func (c *Client) reject(dest bactype.Address, invokeID uint8, reason bactype.RejectReason) error {
+	return c.reply(dest, bactype.APDU{
+		DataType: bactype.Reject,
+		InvokeId: invokeID,
+		Reason:   uint8(reason),
+	})
+}
+
+// abort ends a transaction that another device started with us.
+func (c *Client) abort(dest bactype.Address, invokeID uint8, reason bactype.AbortReason) error {
+	return c.reply(dest, bactype.APDU{
+		DataType: bactype.Abort,
+		Server:   true,
+		InvokeId: invokeID,
+		Reason:   uint8(reason),
+	})
+}
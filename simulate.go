@@ -0,0 +1,199 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/property"
+	"github.com/alexbeltran/gobacnet/store"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Simulate stands up a replica of each of the devices, such as the devices
+// saved by baccli discover, so that integrations can be developed without
+// access to the real site. Each device is served by its own client on the
+// interface, listening on port for the first device and counting up from
+// there. The devices answer who is, read property and read property multiple
+// with the captured names and descriptions, along with the present values,
+// units and state text that ObjectsWithValues captures. Objects of types that
+// cannot be served are skipped. Close every returned client when done.
+func Simulate(inter string, port int, devices []bactype.Device) ([]*Client, error) {
+	if port == 0 {
+		port = DefaultPort
+	}
+	clients := make([]*Client, 0, len(devices))
+	for i, dev := range devices {
+		options := []ClientOption{
+			DeviceInstance(dev.ID.Instance),
+			DeviceVendor(dev.Vendor),
+		}
+		// We do not segment replies, so the captured segmentation is not
+		// announced.
+		if dev.MaxApdu != 0 {
+			options = append(options, DeviceMaxApdu(dev.MaxApdu))
+		}
+		c, err := NewClient(inter, port+i, options...)
+		if err != nil {
+			if c != nil {
+				c.Close()
+			}
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, fmt.Errorf("unable to simulate device %d: %v", dev.ID.Instance, err)
+		}
+		clients = append(clients, c)
+
+		for _, err := range simulateObjects(c.objects, dev) {
+			c.log.Errorf("device %d: %v", dev.ID.Instance, err)
+		}
+	}
+	return clients, nil
+}
+
+// simulateObjects adds the objects of a captured device to the store. Objects
+// that could not be added are returned as errors.
+func simulateObjects(s *store.Store, dev bactype.Device) []error {
+	var errs []error
+	for _, obj := range dev.ObjectSlice() {
+		if obj.ID == s.Device() {
+			if obj.Name != "" {
+				s.SetProperty(obj.ID, property.ObjectName, obj.Name)
+			}
+			if obj.Description != "" {
+				s.SetProperty(obj.ID, property.Description, obj.Description)
+			}
+			simulateProperties(s, obj)
+			continue
+		}
+
+		props := obj.Properties
+		obj.Properties = nil
+		if obj.Name == "" {
+			obj.Name = fmt.Sprintf("%v %d", obj.ID.Type, obj.ID.Instance)
+		}
+		if err := s.Add(obj); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		obj.Properties = props
+		simulateProperties(s, obj)
+	}
+	return errs
+}
+
+// simulateProperties sets the captured property values of an object that has
+// been added to the store. The present value is set last since it is checked
+// against the number of states of multi-state objects. The captured present
+// value of commandable objects becomes their relinquish default.
+func simulateProperties(s *store.Store, obj bactype.Object) {
+	_, err := s.ReadProperty(obj.ID, property.PriorityArray, bactype.ArrayAll)
+	commandable := err == nil
+
+	props := make([]bactype.Property, 0, len(obj.Properties))
+	for _, prop := range obj.Properties {
+		if prop.Type != property.PresentValue {
+			props = append(props, prop)
+		}
+	}
+	for _, prop := range obj.Properties {
+		if prop.Type == property.PresentValue {
+			props = append(props, prop)
+		}
+	}
+
+	for _, prop := range props {
+		if prop.ArrayIndex != bactype.ArrayAll && prop.ArrayIndex != 0 {
+			continue
+		}
+		switch prop.Type {
+		case property.ObjectIdentifier, property.ObjectType, property.ObjectName,
+			property.ObjectList, property.PriorityArray, property.ProtocolServicesSupported:
+			continue
+		}
+
+		current, _ := s.ReadProperty(obj.ID, prop.Type, bactype.ArrayAll)
+		value, ok := jsonValue(current, prop.Data)
+		if !ok {
+			continue
+		}
+		if prop.Type == property.PresentValue && commandable {
+			s.WriteProperty(obj.ID, property.RelinquishDefault, bactype.ArrayAll, value, bactype.NoPriority)
+			continue
+		}
+		s.SetProperty(obj.ID, prop.Type, value)
+	}
+}
+
+// jsonValue converts a property value loaded from JSON to the type of the
+// current value of the property, since JSON loses the type of numbers. Values
+// whose type cannot be recovered are not ok.
+func jsonValue(current, value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string, bool:
+		return v, true
+	case float64:
+		switch current.(type) {
+		case float32:
+			return float32(v), true
+		case float64:
+			return v, true
+		case uint32:
+			return uint32(v), true
+		case int32:
+			return int32(v), true
+		case bactype.Enumerated:
+			return bactype.Enumerated(v), true
+		}
+	case []interface{}:
+		if _, ok := current.(bactype.BitString); !ok {
+			// Lists of text, such as the state text, need no conversion
+			for _, s := range v {
+				if _, ok := s.(string); !ok {
+					return nil, false
+				}
+			}
+			return v, true
+		}
+		bits := make(bactype.BitString, len(v))
+		for i, b := range v {
+			bit, ok := b.(bool)
+			if !ok {
+				return nil, false
+			}
+			bits[i] = bit
+		}
+		return bits, true
+	}
+	return nil, false
+}
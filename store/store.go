@@ -0,0 +1,449 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+// Package store holds the objects of a local BACnet device so that their
+// properties can be served to other devices on the network.
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Protocol version and revision of the local device
+const (
+	protocolVersion  = 1
+	protocolRevision = 14
+)
+
+// Defaults of the device object
+const (
+	defaultApduTimeout = 3000
+	defaultApduRetries = 3
+	noUnits            = 95
+)
+
+// object holds the values of the properties of a single object along with
+// the properties other devices may write to.
+type object struct {
+	typ      bactype.ObjectType
+	values   map[uint32]interface{}
+	writable map[uint32]bool
+}
+
+// Store holds the device object of the local device along with the objects
+// registered with Add. It is safe to use from multiple goroutines.
+type Store struct {
+	mutex    sync.RWMutex
+	device   bactype.ObjectID
+	objects  map[bactype.ObjectID]*object
+	revision uint32
+
+	// onWrite is called after another device wrote to a property
+	onWrite WriteHandler
+
+	// watchers are called after any property changed
+	watchers []ChangeHandler
+}
+
+// New creates a store holding the device object of the given device.
+func New(dev bactype.Device) *Store {
+	s := &Store{
+		device:  dev.ID,
+		objects: make(map[bactype.ObjectID]*object),
+	}
+	values := map[uint32]interface{}{
+		property.ObjectIdentifier:             dev.ID,
+		property.ObjectName:                   fmt.Sprintf("Device %d", dev.ID.Instance),
+		property.ObjectType:                   bactype.Enumerated(bactype.DeviceType),
+		property.SystemStatus:                 bactype.Enumerated(0),
+		property.VendorName:                   "",
+		property.VendorIdentifier:             dev.Vendor,
+		property.ModelName:                    "gobacnet",
+		property.FirmwareRevision:             "",
+		property.ApplicationSoftwareVersion:   "",
+		property.ProtocolVersion:              uint32(protocolVersion),
+		property.ProtocolRevision:             uint32(protocolRevision),
+		property.ProtocolServicesSupported:    servicesSupported(),
+		property.ProtocolObjectTypesSupported: objectTypesSupported(),
+		property.MaxApduLengthAccepted:        dev.MaxApdu,
+		property.SegmentationSupported:        dev.Segmentation,
+		property.ApduTimeout:                  uint32(defaultApduTimeout),
+		property.NumberOfApduRetries:          uint32(defaultApduRetries),
+		property.DeviceAddressBinding:         []interface{}{},
+	}
+	s.objects[dev.ID] = &object{
+		typ:      bactype.DeviceType,
+		values:   values,
+		writable: make(map[uint32]bool),
+	}
+	return s
+}
+
+// defaults returns the properties every object of the given type starts with,
+// or false when the type cannot be added to the store. The present value of
+// inputs is only writable while they are out of service, which is checked on
+// every write.
+func defaults(t bactype.ObjectType) (*object, bool) {
+	o := &object{
+		typ: t,
+		values: map[uint32]interface{}{
+			property.StatusFlags:  bactype.BitString{false, false, false, false},
+			property.EventState:   bactype.Enumerated(0),
+			property.OutOfService: false,
+		},
+		writable: map[uint32]bool{
+			property.PresentValue: true,
+			property.Description:  true,
+			property.OutOfService: true,
+		},
+	}
+	switch t {
+	case bactype.AnalogInput, bactype.AnalogOutput, bactype.AnalogValue:
+		o.values[property.PresentValue] = float32(0)
+		o.values[property.Units] = bactype.Enumerated(noUnits)
+		o.values[property.COVIncrement] = float32(0)
+		o.writable[property.COVIncrement] = true
+	case bactype.BinaryInput, bactype.BinaryOutput:
+		o.values[property.PresentValue] = bactype.Enumerated(0)
+		o.values[property.Polarity] = bactype.Enumerated(0)
+	case bactype.BinaryValue:
+		o.values[property.PresentValue] = bactype.Enumerated(0)
+	case bactype.MultiStateInput, bactype.MultiStateOutput, bactype.MultiStateValue:
+		o.values[property.PresentValue] = uint32(1)
+		o.values[property.NumberOfStates] = uint32(2)
+	default:
+		return nil, false
+	}
+	return o, true
+}
+
+// supportedTypes are the object types that can be added to the store
+var supportedTypes = []bactype.ObjectType{
+	bactype.AnalogInput, bactype.AnalogOutput, bactype.AnalogValue,
+	bactype.BinaryInput, bactype.BinaryOutput, bactype.BinaryValue,
+	bactype.DeviceType,
+	bactype.MultiStateInput, bactype.MultiStateOutput, bactype.MultiStateValue,
+}
+
+func objectTypesSupported() bactype.BitString {
+	max := supportedTypes[len(supportedTypes)-1]
+	b := make(bactype.BitString, max+1)
+	for _, t := range supportedTypes {
+		b[t] = true
+	}
+	return b
+}
+
+// Positions of the services executed by a client serving the store within the
+// protocol services supported bit string of the device object
+const (
+	servicesSupportedLen         = 41
+	supportsSubscribeCOV         = 5
+	supportsReadProperty         = 12
+	supportsReadPropertyMultiple = 14
+	supportsWriteProperty        = 15
+	supportsWhoIs                = 34
+	supportsSubscribeCOVProperty = 38
+)
+
+var servicesExecuted = []int{
+	supportsSubscribeCOV,
+	supportsReadProperty,
+	supportsReadPropertyMultiple,
+	supportsWriteProperty,
+	supportsWhoIs,
+	supportsSubscribeCOVProperty,
+}
+
+func servicesSupported() bactype.BitString {
+	b := make(bactype.BitString, servicesSupportedLen)
+	for _, i := range servicesExecuted {
+		b[i] = true
+	}
+	return b
+}
+
+// requiredProperties are the properties the standard requires of each object
+// type on top of the identifier, name and type of every object. The priority
+// array and relinquish default are required of commandable objects.
+var requiredProperties = map[bactype.ObjectType][]uint32{
+	bactype.DeviceType: {
+		property.SystemStatus, property.VendorName, property.VendorIdentifier,
+		property.ModelName, property.FirmwareRevision,
+		property.ApplicationSoftwareVersion, property.ProtocolVersion,
+		property.ProtocolRevision, property.ProtocolServicesSupported,
+		property.ProtocolObjectTypesSupported, property.ObjectList,
+		property.MaxApduLengthAccepted, property.SegmentationSupported,
+		property.ApduTimeout, property.NumberOfApduRetries,
+		property.DeviceAddressBinding, property.DatabaseRevision,
+	},
+	bactype.AnalogInput:      {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.Units},
+	bactype.AnalogOutput:     {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.Units},
+	bactype.AnalogValue:      {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.Units},
+	bactype.BinaryInput:      {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.Polarity},
+	bactype.BinaryOutput:     {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.Polarity},
+	bactype.BinaryValue:      {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService},
+	bactype.MultiStateInput:  {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.NumberOfStates},
+	bactype.MultiStateOutput: {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.NumberOfStates},
+	bactype.MultiStateValue:  {property.PresentValue, property.StatusFlags, property.EventState, property.OutOfService, property.NumberOfStates},
+}
+
+func isRequired(t bactype.ObjectType, prop uint32) bool {
+	switch prop {
+	case property.ObjectIdentifier, property.ObjectName, property.ObjectType,
+		property.PriorityArray, property.RelinquishDefault:
+		return true
+	}
+	for _, p := range requiredProperties[t] {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Device returns the identifier of the device object.
+func (s *Store) Device() bactype.ObjectID {
+	return s.device
+}
+
+// Add registers an analog, binary or multi-state object. The object must have
+// a name that is unique within the device. Any properties of the object are
+// set on top of the defaults of its type.
+//
+// Outputs are commandable, as are values added with a RelinquishDefault
+// property. Commandable objects have a priority array and their present value
+// is the value of the highest priority that has been written, or the
+// relinquish default when every priority has been relinquished.
+func (s *Store) Add(obj bactype.Object) error {
+	o, ok := defaults(obj.ID.Type)
+	if !ok {
+		return fmt.Errorf("objects of type %v are not supported", obj.ID.Type)
+	}
+	if obj.Name == "" {
+		return fmt.Errorf("object %v has no name", obj.ID)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.objects[obj.ID]; ok {
+		return fmt.Errorf("object %v already exists", obj.ID)
+	}
+	for id, other := range s.objects {
+		if other.values[property.ObjectName] == obj.Name {
+			return fmt.Errorf("object %v is already named %q", id, obj.Name)
+		}
+	}
+
+	o.values[property.ObjectIdentifier] = obj.ID
+	o.values[property.ObjectName] = obj.Name
+	o.values[property.ObjectType] = bactype.Enumerated(obj.ID.Type)
+	if obj.Description != "" {
+		o.values[property.Description] = obj.Description
+	}
+	for _, prop := range obj.Properties {
+		o.values[prop.Type] = prop.Data
+	}
+	if isOutput(obj.ID.Type) || o.values[property.RelinquishDefault] != nil {
+		o.makeCommandable()
+	}
+	s.objects[obj.ID] = o
+	s.revision++
+	return nil
+}
+
+// Remove deletes an object from the store. The device object cannot be
+// removed.
+func (s *Store) Remove(id bactype.ObjectID) error {
+	if id == s.device {
+		return fmt.Errorf("the device object cannot be removed")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.objects[id]; !ok {
+		return fmt.Errorf("object %v does not exist", id)
+	}
+	delete(s.objects, id)
+	s.revision++
+	return nil
+}
+
+// Objects returns the identifiers of every object in the store, ordered by
+// type and instance.
+func (s *Store) Objects() []bactype.ObjectID {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.objectList()
+}
+
+func (s *Store) objectList() []bactype.ObjectID {
+	ids := make([]bactype.ObjectID, 0, len(s.objects))
+	for id := range s.objects {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Type != ids[j].Type {
+			return ids[i].Type < ids[j].Type
+		}
+		return ids[i].Instance < ids[j].Instance
+	})
+	return ids
+}
+
+// Properties returns the identifiers of every property of the object in
+// ascending order.
+func (s *Store) Properties(id bactype.ObjectID) ([]uint32, error) {
+	return s.properties(id, func(bool) bool { return true })
+}
+
+// RequiredProperties returns the identifiers of the properties of the object
+// that the standard requires in ascending order.
+func (s *Store) RequiredProperties(id bactype.ObjectID) ([]uint32, error) {
+	return s.properties(id, func(required bool) bool { return required })
+}
+
+// OptionalProperties returns the identifiers of the properties of the object
+// that the standard does not require in ascending order.
+func (s *Store) OptionalProperties(id bactype.ObjectID) ([]uint32, error) {
+	return s.properties(id, func(required bool) bool { return !required })
+}
+
+// properties returns the properties of the object for which include returns
+// true when passed if the property is required.
+func (s *Store) properties(id bactype.ObjectID, include func(required bool) bool) ([]uint32, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	o, ok := s.objects[id]
+	if !ok {
+		return nil, propertyError(bactype.ErrorClassObject, bactype.ErrorCodeUnknownObject)
+	}
+	props := make([]uint32, 0, len(o.values)+2)
+	for p := range o.values {
+		props = append(props, p)
+	}
+	if id == s.device {
+		props = append(props, property.ObjectList, property.DatabaseRevision)
+	}
+
+	n := 0
+	for _, p := range props {
+		if include(isRequired(o.typ, p)) {
+			props[n] = p
+			n++
+		}
+	}
+	props = props[:n]
+	sort.Slice(props, func(i, j int) bool { return props[i] < props[j] })
+	return props, nil
+}
+
+// ReadProperty returns the value of a property. Lists and arrays are returned
+// as a []interface{} unless an array index is given, where index 0 is the
+// length of the array. Failures are returned as a *bactype.PropertyError
+// holding the error class and code to reply with.
+func (s *Store) ReadProperty(id bactype.ObjectID, prop uint32, index uint32) (interface{}, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	o, ok := s.objects[id]
+	if !ok {
+		return nil, propertyError(bactype.ErrorClassObject, bactype.ErrorCodeUnknownObject)
+	}
+
+	var value interface{}
+	switch {
+	case id == s.device && prop == property.ObjectList:
+		ids := s.objectList()
+		list := make([]interface{}, len(ids))
+		for i, id := range ids {
+			list[i] = id
+		}
+		value = list
+	case id == s.device && prop == property.DatabaseRevision:
+		value = s.revision
+	default:
+		value, ok = o.values[prop]
+		if !ok {
+			return nil, propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeUnknownProperty)
+		}
+	}
+
+	if index == bactype.ArrayAll {
+		return value, nil
+	}
+	array, ok := value.([]interface{})
+	if !ok {
+		return nil, propertyError(bactype.ErrorClassProperty, bactype.ErrorCodePropertyIsNotAnArray)
+	}
+	if index == 0 {
+		return uint32(len(array)), nil
+	}
+	if int(index) > len(array) {
+		return nil, propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeInvalidArrayIndex)
+	}
+	return array[index-1], nil
+}
+
+// SetProperty sets the value of a property, adding the property to the object
+// if it does not have it yet. Values are not checked against the type of the
+// property. Use WriteProperty to command the present value of a commandable
+// object, since setting it directly is undone by the next write.
+func (s *Store) SetProperty(id bactype.ObjectID, prop uint32, value interface{}) error {
+	switch prop {
+	case property.ObjectIdentifier, property.ObjectType, property.ObjectList, property.DatabaseRevision:
+		return fmt.Errorf("property %s cannot be set", property.String(prop))
+	}
+
+	s.mutex.Lock()
+	o, ok := s.objects[id]
+	if !ok {
+		s.mutex.Unlock()
+		return fmt.Errorf("object %v does not exist", id)
+	}
+	before := o.snapshot()
+	o.set(prop, value)
+	changes := o.changes(prop, before)
+	watchers := s.watchers
+	s.mutex.Unlock()
+
+	s.notify(id, watchers, changes)
+	return nil
+}
+
+// propertyError returns the error class and code a read or write is answered
+// with.
+func propertyError(class bactype.ErrorClass, code bactype.ErrorCode) error {
+	return &bactype.PropertyError{Class: class, Code: code}
+}
@@ -0,0 +1,337 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+func newTestStore(t *testing.T) *Store {
+	s := New(bactype.Device{
+		ID:           bactype.ObjectID{Type: bactype.DeviceType, Instance: 1234},
+		MaxApdu:      bactype.MaxAPDUOverIP,
+		Segmentation: bactype.NoSegmentation,
+		Vendor:       260,
+	})
+	err := s.Add(bactype.Object{
+		ID:   bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1},
+		Name: "Zone Temp",
+		Properties: []bactype.Property{
+			{Type: property.PresentValue, Data: float32(21.5)},
+			{Type: property.StateText, Data: []interface{}{"Off", "On"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	s := newTestStore(t)
+	dev := s.Device()
+	av := bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1}
+
+	tests := []struct {
+		id    bactype.ObjectID
+		prop  uint32
+		index uint32
+		value interface{}
+	}{
+		{av, property.PresentValue, bactype.ArrayAll, float32(21.5)},
+		{av, property.ObjectName, bactype.ArrayAll, "Zone Temp"},
+		{av, property.ObjectType, bactype.ArrayAll, bactype.Enumerated(bactype.AnalogValue)},
+		{av, property.StateText, 0, uint32(2)},
+		{av, property.StateText, 2, "On"},
+		{dev, property.VendorIdentifier, bactype.ArrayAll, uint32(260)},
+		{dev, property.ObjectList, bactype.ArrayAll, []interface{}{av, dev}},
+		{dev, property.ObjectList, 1, av},
+		{dev, property.DatabaseRevision, bactype.ArrayAll, uint32(1)},
+	}
+	for _, test := range tests {
+		value, err := s.ReadProperty(test.id, test.prop, test.index)
+		if err != nil {
+			t.Errorf("Reading %s[%d] of %v: %v", property.String(test.prop), test.index, test.id, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, test.value) {
+			t.Errorf("Read %s[%d] of %v: expected %v, got %v", property.String(test.prop),
+				test.index, test.id, test.value, value)
+		}
+	}
+
+	services, _ := s.ReadProperty(dev, property.ProtocolServicesSupported, bactype.ArrayAll)
+	if b, ok := services.(bactype.BitString); !ok || !b[supportsReadProperty] {
+		t.Errorf("Read property is not in the services supported: %v", services)
+	}
+
+	required, _ := s.RequiredProperties(av)
+	expected := []uint32{property.EventState, property.ObjectIdentifier, property.ObjectName, property.ObjectType,
+		property.OutOfService, property.PresentValue, property.StatusFlags, property.Units}
+	if !reflect.DeepEqual(required, expected) {
+		t.Errorf("Expected the required properties %v, got %v", expected, required)
+	}
+	optional, _ := s.OptionalProperties(av)
+	expected = []uint32{property.COVIncrement, property.StateText}
+	if !reflect.DeepEqual(optional, expected) {
+		t.Errorf("Expected the optional properties %v, got %v", expected, optional)
+	}
+
+	if err := s.SetProperty(av, property.PresentValue, float32(22)); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.ReadProperty(av, property.PresentValue, bactype.ArrayAll); v != float32(22) {
+		t.Errorf("Present value was not set, got %v", v)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	s := newTestStore(t)
+	av := bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1}
+
+	tests := []struct {
+		id    bactype.ObjectID
+		prop  uint32
+		index uint32
+		code  bactype.ErrorCode
+	}{
+		{bactype.ObjectID{Type: bactype.AnalogValue, Instance: 2}, property.PresentValue, bactype.ArrayAll, bactype.ErrorCodeUnknownObject},
+		{av, property.FileSize, bactype.ArrayAll, bactype.ErrorCodeUnknownProperty},
+		{av, property.PresentValue, 1, bactype.ErrorCodePropertyIsNotAnArray},
+		{av, property.StateText, 3, bactype.ErrorCodeInvalidArrayIndex},
+	}
+	for _, test := range tests {
+		_, err := s.ReadProperty(test.id, test.prop, test.index)
+		perr, ok := err.(*bactype.PropertyError)
+		if !ok || perr.Code != test.code {
+			t.Errorf("Reading %s[%d] of %v: expected %v, got %v", property.String(test.prop),
+				test.index, test.id, test.code, err)
+		}
+	}
+
+	dup := bactype.Object{ID: bactype.ObjectID{Type: bactype.BinaryValue, Instance: 1}, Name: "Zone Temp"}
+	if err := s.Add(dup); err == nil {
+		t.Error("Added an object with a duplicate name")
+	}
+	if err := s.Add(bactype.Object{ID: bactype.ObjectID{Type: bactype.File, Instance: 1}, Name: "File"}); err == nil {
+		t.Error("Added an unsupported file object")
+	}
+	if err := s.Remove(s.Device()); err == nil {
+		t.Error("Removed the device object")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	s := newTestStore(t)
+	bo := bactype.ObjectID{Type: bactype.BinaryOutput, Instance: 1}
+	if err := s.Add(bactype.Object{ID: bo, Name: "Fan"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var written []interface{}
+	s.HandleWrites(func(id bactype.ObjectID, prop uint32, value interface{}) {
+		if id != bo {
+			t.Errorf("Unexpected write to %v", id)
+		}
+		if prop == property.PresentValue {
+			written = append(written, value)
+		}
+	})
+
+	on, off := bactype.Enumerated(1), bactype.Enumerated(0)
+	writes := []struct {
+		value    interface{}
+		priority uint8
+	}{
+		{on, 8},
+		{off, 0},  // below priority 8, so it stays on
+		{nil, 8},  // priority 16 takes over
+		{nil, 16}, // back to the relinquish default
+		{on, 1},
+	}
+	for _, w := range writes {
+		if err := s.WriteProperty(bo, property.PresentValue, bactype.ArrayAll, w.value, w.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []interface{}{on, on, off, off, on}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected present values %v, got %v", expected, written)
+	}
+
+	priorities, _ := s.ReadProperty(bo, property.PriorityArray, bactype.ArrayAll)
+	if p := priorities.([]interface{}); len(p) != 16 || p[0] != on || p[7] != nil || p[15] != nil {
+		t.Errorf("Unexpected priority array %v", p)
+	}
+
+	// The relinquish default is used once every priority is relinquished
+	s.WriteProperty(bo, property.RelinquishDefault, bactype.ArrayAll, on, bactype.NoPriority)
+	s.WriteProperty(bo, property.PresentValue, bactype.ArrayAll, nil, 1)
+	if v, _ := s.ReadProperty(bo, property.PresentValue, bactype.ArrayAll); v != on {
+		t.Errorf("Present value should be the relinquish default, got %v", v)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	s := newTestStore(t)
+	av := bactype.ObjectID{Type: bactype.AnalogValue, Instance: 1}
+	ai := bactype.ObjectID{Type: bactype.AnalogInput, Instance: 1}
+	msv := bactype.ObjectID{Type: bactype.MultiStateValue, Instance: 1}
+	if err := s.Add(bactype.Object{ID: ai, Name: "Outside Air"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(bactype.Object{ID: msv, Name: "Mode"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id       bactype.ObjectID
+		prop     uint32
+		value    interface{}
+		priority uint8
+		code     bactype.ErrorCode
+	}{
+		{av, property.PresentValue, "hot", 0, bactype.ErrorCodeInvalidDataType},
+		{av, property.PresentValue, nil, 0, bactype.ErrorCodeInvalidDataType},
+		{av, property.ObjectName, "Renamed", 0, bactype.ErrorCodeWriteAccessDenied},
+		{av, property.FileSize, uint32(1), 0, bactype.ErrorCodeUnknownProperty},
+		{ai, property.PresentValue, float32(3), 0, bactype.ErrorCodeWriteAccessDenied},
+		{msv, property.PresentValue, uint32(3), 0, bactype.ErrorCodeValueOutOfRange},
+	}
+	for _, test := range tests {
+		err := s.WriteProperty(test.id, test.prop, bactype.ArrayAll, test.value, test.priority)
+		perr, ok := err.(*bactype.PropertyError)
+		if !ok || perr.Code != test.code {
+			t.Errorf("Writing %v to %s of %v: expected %v, got %v", test.value,
+				property.String(test.prop), test.id, test.code, err)
+		}
+	}
+
+	// Inputs can be written while out of service
+	if err := s.WriteProperty(ai, property.OutOfService, bactype.ArrayAll, true, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteProperty(ai, property.PresentValue, bactype.ArrayAll, float32(3), 0); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := newTestStore(t)
+	bo := bactype.ObjectID{Type: bactype.BinaryOutput, Instance: 1}
+	if err := s.Add(bactype.Object{ID: bo, Name: "Fan"}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(map[uint32]interface{})
+	s.Watch(func(id bactype.ObjectID, prop uint32, value interface{}) {
+		if id != bo {
+			t.Errorf("Unexpected change of %v", id)
+		}
+		changes[prop] = value
+	})
+
+	// Commanding changes the priority array along with the present value
+	on := bactype.Enumerated(1)
+	if err := s.WriteProperty(bo, property.PresentValue, bactype.ArrayAll, on, 8); err != nil {
+		t.Fatal(err)
+	}
+	if changes[property.PresentValue] != on {
+		t.Errorf("Expected present value %v, got %v", on, changes[property.PresentValue])
+	}
+
+	// Taking an object out of service sets its status flag
+	if err := s.SetProperty(bo, property.OutOfService, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := bactype.BitString{false, false, false, true}
+	if !reflect.DeepEqual(changes[property.StatusFlags], expected) {
+		t.Errorf("Expected status flags %v, got %v", expected, changes[property.StatusFlags])
+	}
+}
+
+// TestDecodedWrite writes values the way they arrive from other devices, where
+// enumerated values are decoded as a uint32.
+func TestDecodedWrite(t *testing.T) {
+	s := newTestStore(t)
+	bo := bactype.ObjectID{Type: bactype.BinaryOutput, Instance: 1}
+	if err := s.Add(bactype.Object{ID: bo, Name: "Fan"}); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(value interface{}) error {
+		e := encoding.NewEncoder()
+		err := e.WriteProperty(1, bactype.WritePropertyData{
+			Object: bactype.Object{
+				ID:         bo,
+				Properties: []bactype.Property{{Type: property.PresentValue, ArrayIndex: bactype.ArrayAll, Data: value}},
+			},
+			Priority: 8,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := encoding.NewDecoder(e.Bytes())
+		var a bactype.APDU
+		if err = d.APDU(&a); err != nil {
+			t.Fatal(err)
+		}
+		var wp bactype.WritePropertyData
+		if err = encoding.NewDecoder(a.RawData).WriteProperty(&wp); err != nil {
+			t.Fatal(err)
+		}
+		prop := wp.Object.Properties[0]
+		return s.WriteProperty(wp.Object.ID, prop.Type, prop.ArrayIndex, prop.Data, wp.Priority)
+	}
+
+	if err := write(bactype.Enumerated(1)); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.ReadProperty(bo, property.PresentValue, bactype.ArrayAll); v != bactype.Enumerated(1) {
+		t.Errorf("Expected the present value to be active, got %v (%T)", v, v)
+	}
+
+	if err := write(bactype.Enumerated(2)); err == nil {
+		t.Error("Binary present value was written out of range")
+	} else if perr, ok := err.(*bactype.PropertyError); !ok || perr.Code != bactype.ErrorCodeValueOutOfRange {
+		t.Errorf("Expected %v, got %v", bactype.ErrorCodeValueOutOfRange, err)
+	}
+
+	if err := write(float32(1)); err == nil {
+		t.Error("Binary present value was written with a real")
+	}
+}
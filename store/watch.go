@@ -0,0 +1,114 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package store
+
+import (
+	"reflect"
+
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// Position of the out of service flag within the status flags
+const outOfServiceFlag = 3
+
+// ChangeHandler is called after the value of a property changed, whether it
+// was set locally or written by another device.
+type ChangeHandler func(id bactype.ObjectID, prop uint32, value interface{})
+
+// Watch adds a handler that is called after each change of a property. Unlike
+// the handler of HandleWrites, any number of handlers can watch the store.
+// Handlers are called after the store has been unlocked, so they may read from
+// the store.
+func (s *Store) Watch(handler ChangeHandler) {
+	s.mutex.Lock()
+	s.watchers = append(s.watchers, handler)
+	s.mutex.Unlock()
+}
+
+// change is a property that changed and its new value
+type change struct {
+	prop  uint32
+	value interface{}
+}
+
+// notify passes the changes of an object to every watcher. It must be called
+// without holding the lock.
+func (s *Store) notify(id bactype.ObjectID, watchers []ChangeHandler, changes []change) {
+	for _, c := range changes {
+		for _, w := range watchers {
+			w(id, c.prop, c.value)
+		}
+	}
+}
+
+// derived are the properties that may change as a side effect of setting
+// another property.
+var derived = []uint32{property.PresentValue, property.StatusFlags}
+
+func (o *object) snapshot() []interface{} {
+	values := make([]interface{}, len(derived))
+	for i, p := range derived {
+		values[i] = o.values[p]
+	}
+	return values
+}
+
+// changes returns the property that was set along with the derived properties
+// that are no longer the same as in the snapshot taken before.
+func (o *object) changes(prop uint32, before []interface{}) []change {
+	changes := []change{{prop, o.values[prop]}}
+	for i, p := range derived {
+		if p != prop && !reflect.DeepEqual(before[i], o.values[p]) {
+			changes = append(changes, change{p, o.values[p]})
+		}
+	}
+	return changes
+}
+
+// set sets the value of a property and keeps the status flags in line with
+// the out of service property.
+func (o *object) set(prop uint32, value interface{}) {
+	o.values[prop] = value
+	if prop != property.OutOfService {
+		return
+	}
+	flags, ok := o.values[property.StatusFlags].(bactype.BitString)
+	outOfService, isBool := value.(bool)
+	if !ok || !isBool || len(flags) <= outOfServiceFlag {
+		return
+	}
+	// Copy the flags since readers may still hold the old ones
+	flags = append(bactype.BitString{}, flags...)
+	flags[outOfServiceFlag] = outOfService
+	o.values[property.StatusFlags] = flags
+}
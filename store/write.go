@@ -0,0 +1,261 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package store
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/alexbeltran/gobacnet/property"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// WriteHandler is called after a property was written. For the present value
+// of commandable objects, value is the new effective present value.
+type WriteHandler func(id bactype.ObjectID, prop uint32, value interface{})
+
+// HandleWrites sets the handler called after each successful WriteProperty.
+// Passing nil stops calling the previous handler.
+func (s *Store) HandleWrites(handler WriteHandler) {
+	s.mutex.Lock()
+	s.onWrite = handler
+	s.mutex.Unlock()
+}
+
+// SetWritable sets whether other devices may write to a property of an object.
+func (s *Store) SetWritable(id bactype.ObjectID, prop uint32, writable bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	o, ok := s.objects[id]
+	if !ok {
+		return fmt.Errorf("object %v does not exist", id)
+	}
+	if writable {
+		o.writable[prop] = true
+	} else {
+		delete(o.writable, prop)
+	}
+	return nil
+}
+
+// WriteProperty writes a property as requested by another device. The value
+// must have the same type as the current value of the property and the
+// property must be writable. A nil value relinquishes the given priority of a
+// commandable present value. The priority is ignored for all other properties.
+// Failures are returned as a *bactype.PropertyError.
+func (s *Store) WriteProperty(id bactype.ObjectID, prop uint32, index uint32, value interface{}, priority uint8) error {
+	s.mutex.Lock()
+	o, ok := s.objects[id]
+	if !ok {
+		s.mutex.Unlock()
+		return propertyError(bactype.ErrorClassObject, bactype.ErrorCodeUnknownObject)
+	}
+	before := o.snapshot()
+	err := o.write(prop, index, value, priority)
+	changes := o.changes(prop, before)
+	handler := s.onWrite
+	watchers := s.watchers
+	s.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if handler != nil {
+		handler(id, prop, changes[0].value)
+	}
+	s.notify(id, watchers, changes)
+	return nil
+}
+
+func (o *object) write(prop uint32, index uint32, value interface{}, priority uint8) error {
+	current, ok := o.values[prop]
+	if !ok {
+		return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeUnknownProperty)
+	}
+	if !o.isWritable(prop) {
+		return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeWriteAccessDenied)
+	}
+
+	if index != bactype.ArrayAll {
+		array, ok := current.([]interface{})
+		if !ok {
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodePropertyIsNotAnArray)
+		}
+		if index == 0 {
+			// Resizing arrays is not supported
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeWriteAccessDenied)
+		}
+		if int(index) > len(array) {
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeInvalidArrayIndex)
+		}
+		value = decoded(array[index-1], value)
+		if !sameType(array[index-1], value) {
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeInvalidDataType)
+		}
+		// Copy the array since readers may still hold the old one
+		array = append([]interface{}{}, array...)
+		array[index-1] = value
+		o.values[prop] = array
+		return nil
+	}
+
+	value = decoded(current, value)
+	if prop == property.PresentValue && o.commandable() {
+		return o.command(value, priority)
+	}
+	if err := o.check(prop, current, value); err != nil {
+		return err
+	}
+	o.set(prop, value)
+	if prop == property.RelinquishDefault && o.commandable() {
+		o.values[property.PresentValue] = o.effectiveValue()
+	}
+	return nil
+}
+
+// isWritable returns if another device may write to the property. The present
+// value of an input is only writable while it is out of service.
+func (o *object) isWritable(prop uint32) bool {
+	if !o.writable[prop] {
+		return false
+	}
+	if prop == property.PresentValue && isInput(o.typ) {
+		outOfService, _ := o.values[property.OutOfService].(bool)
+		return outOfService
+	}
+	return true
+}
+
+// check validates that value may replace the current value of a property.
+func (o *object) check(prop uint32, current, value interface{}) error {
+	if !sameType(current, value) {
+		return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeInvalidDataType)
+	}
+	if prop != property.PresentValue && prop != property.RelinquishDefault {
+		return nil
+	}
+
+	switch o.typ {
+	case bactype.BinaryInput, bactype.BinaryOutput, bactype.BinaryValue:
+		if v, ok := value.(bactype.Enumerated); ok && v > 1 {
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeValueOutOfRange)
+		}
+	case bactype.MultiStateInput, bactype.MultiStateOutput, bactype.MultiStateValue:
+		states, _ := o.values[property.NumberOfStates].(uint32)
+		if v, ok := value.(uint32); ok && (v < 1 || v > states) {
+			return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeValueOutOfRange)
+		}
+	}
+	return nil
+}
+
+// command writes the present value of a commandable object at the given
+// priority, or relinquishes the priority when value is nil. Writes without a
+// priority are made at the lowest priority.
+func (o *object) command(value interface{}, priority uint8) error {
+	if priority == bactype.NoPriority {
+		priority = bactype.MaxPriority
+	}
+	if priority > bactype.MaxPriority {
+		return propertyError(bactype.ErrorClassProperty, bactype.ErrorCodeValueOutOfRange)
+	}
+	if value != nil {
+		err := o.check(property.PresentValue, o.values[property.RelinquishDefault], value)
+		if err != nil {
+			return err
+		}
+	}
+
+	priorities := append([]interface{}{}, o.values[property.PriorityArray].([]interface{})...)
+	priorities[priority-1] = value
+	o.values[property.PriorityArray] = priorities
+	o.values[property.PresentValue] = o.effectiveValue()
+	return nil
+}
+
+// effectiveValue returns the value of the highest priority that is set, or the
+// relinquish default when every priority has been relinquished.
+func (o *object) effectiveValue() interface{} {
+	for _, v := range o.values[property.PriorityArray].([]interface{}) {
+		if v != nil {
+			return v
+		}
+	}
+	return o.values[property.RelinquishDefault]
+}
+
+// makeCommandable adds a priority array to the object. Without a relinquish
+// default, the present value the object was added with is used.
+func (o *object) makeCommandable() {
+	if _, ok := o.values[property.RelinquishDefault]; !ok {
+		o.values[property.RelinquishDefault] = o.values[property.PresentValue]
+	}
+	o.values[property.PriorityArray] = make([]interface{}, bactype.MaxPriority)
+	o.values[property.PresentValue] = o.effectiveValue()
+	o.writable[property.RelinquishDefault] = true
+}
+
+func (o *object) commandable() bool {
+	_, ok := o.values[property.PriorityArray]
+	return ok
+}
+
+// decoded converts a value decoded from a request to the type of the current
+// value of the property. The decoder returns enumerated values as a uint32
+// since it cannot tell which enumeration they belong to.
+func decoded(current, value interface{}) interface{} {
+	v, ok := value.(uint32)
+	if _, isEnum := current.(bactype.Enumerated); ok && isEnum {
+		return bactype.Enumerated(v)
+	}
+	return value
+}
+
+func sameType(a, b interface{}) bool {
+	return a != nil && b != nil && reflect.TypeOf(a) == reflect.TypeOf(b)
+}
+
+func isInput(t bactype.ObjectType) bool {
+	switch t {
+	case bactype.AnalogInput, bactype.BinaryInput, bactype.MultiStateInput:
+		return true
+	}
+	return false
+}
+
+func isOutput(t bactype.ObjectType) bool {
+	switch t {
+	case bactype.AnalogOutput, bactype.BinaryOutput, bactype.MultiStateOutput:
+		return true
+	}
+	return false
+}
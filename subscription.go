@@ -0,0 +1,191 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import "sync"
+
+// subscriptionBufferSize is the number of values that are buffered per
+// subscription. Values received while the buffer is full are dropped, see
+// deliver.
+const subscriptionBufferSize = 20
+
+// subscription delivers the values received by the client, such as change of
+// value or event notifications, to the channel of a subscriber.
+type subscription[T any] struct {
+	// id is unique within the manager of the subscription. Change of value
+	// subscriptions use it as their subscriber process id.
+	id uint32
+
+	// match selects the values that are delivered. Every value matches when
+	// nil.
+	match func(T) bool
+
+	// subscribe sends the subscription request to the device for
+	// subscriptions that are held by a device and is nil otherwise. Cancel is
+	// set to remove the subscription from the device.
+	subscribe func(cancel bool) error
+
+	data   chan T
+	done   chan struct{}
+	mutex  sync.Mutex
+	closed bool
+}
+
+// deliver passes the value to the subscriber unless the subscription has been
+// stopped. Values are delivered from the goroutine that handles incoming
+// packets, so deliver never waits on the subscriber: the value is dropped when
+// the buffer of the subscriber is full, in which case false is returned.
+func (s *subscription[T]) deliver(v T) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return true
+	}
+	select {
+	case s.data <- v:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop prevents any further values and closes the data channel
+func (s *subscription[T]) stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+	close(s.data)
+}
+
+// cancel stops the subscription and removes it from the device if it is held
+// by one.
+func (s *subscription[T]) cancel() error {
+	s.stop()
+	if s.subscribe == nil {
+		return nil
+	}
+	return s.subscribe(true)
+}
+
+// subscriptionManager keeps track of the subscriptions to one kind of value by
+// their id.
+type subscriptionManager[T any] struct {
+	mutex  sync.Mutex
+	lastID uint32
+	subs   map[uint32]*subscription[T]
+}
+
+// new registers a subscription under an unused id. Subscribe builds the
+// function that sends the subscription request for the id and may be nil. It
+// is called before the subscription is registered so that the subscription
+// can be cancelled as soon as it can be found.
+func (m *subscriptionManager[T]) new(match func(T) bool, subscribe func(id uint32) func(cancel bool) error) *subscription[T] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.subs == nil {
+		m.subs = make(map[uint32]*subscription[T])
+	}
+
+	// Id 0 is skipped since some devices treat process id 0 as unused
+	for {
+		m.lastID++
+		if _, ok := m.subs[m.lastID]; !ok && m.lastID != 0 {
+			break
+		}
+	}
+
+	s := &subscription[T]{
+		id:    m.lastID,
+		match: match,
+		data:  make(chan T, subscriptionBufferSize),
+		done:  make(chan struct{}),
+	}
+	if subscribe != nil {
+		s.subscribe = subscribe(s.id)
+	}
+	m.subs[s.id] = s
+	return s
+}
+
+func (m *subscriptionManager[T]) get(id uint32) *subscription[T] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.subs[id]
+}
+
+func (m *subscriptionManager[T]) remove(id uint32) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.subs, id)
+}
+
+// matching returns the subscriptions that the value is delivered to so they
+// can be delivered to without holding the lock.
+func (m *subscriptionManager[T]) matching(v T) []*subscription[T] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var subs []*subscription[T]
+	for _, s := range m.subs {
+		if s.match == nil || s.match(v) {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+// removeChannel removes the subscription that delivers to the channel
+func (m *subscriptionManager[T]) removeChannel(ch <-chan T) *subscription[T] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, s := range m.subs {
+		if (<-chan T)(s.data) == ch {
+			delete(m.subs, id)
+			return s
+		}
+	}
+	return nil
+}
+
+func (m *subscriptionManager[T]) removeAll() []*subscription[T] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	subs := make([]*subscription[T], 0, len(m.subs))
+	for id, s := range m.subs {
+		subs = append(subs, s)
+		delete(m.subs, id)
+	}
+	return subs
+}
@@ -0,0 +1,101 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// TextMessageHandler is called with every text message sent to the client
+type TextMessageHandler func(msg bactype.TextMessage)
+
+// textMessageHandler holds the handler set by HandleTextMessages
+type textMessageHandler struct {
+	mutex   sync.Mutex
+	handler TextMessageHandler
+}
+
+func (h *textMessageHandler) set(handler TextMessageHandler) {
+	h.mutex.Lock()
+	h.handler = handler
+	h.mutex.Unlock()
+}
+
+func (h *textMessageHandler) get() TextMessageHandler {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.handler
+}
+
+// TextMessage sends a text message to a device, which acknowledges that it
+// received it. The Source of the message is the device object of the sender.
+func (c *Client) TextMessage(dev bactype.Device, msg bactype.TextMessage) error {
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.ConfirmedTextMessage(id, msg)
+	})
+}
+
+// UnconfirmedTextMessage sends a text message to dest, which may be a single
+// device or a broadcast address.
+func (c *Client) UnconfirmedTextMessage(dest bactype.Address, msg bactype.TextMessage) error {
+	return c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+		return enc.UnconfirmedTextMessage(msg)
+	})
+}
+
+// HandleTextMessages sets the handler that is called with the confirmed and
+// unconfirmed text messages sent to the client. Confirmed messages are
+// acknowledged once the handler returns. A nil handler discards the messages.
+func (c *Client) HandleTextMessages(handler TextMessageHandler) {
+	c.textMessages.set(handler)
+}
+
+// handleTextMessage passes a text message sent by a device to the handler
+func (c *Client) handleTextMessage(src bactype.Address, b []byte) error {
+	var msg bactype.TextMessage
+	if err := encoding.NewDecoder(b).TextMessage(&msg); err != nil {
+		return fmt.Errorf("unable to decode text message: %v", err)
+	}
+	msg.Addr = src
+
+	handler := c.textMessages.get()
+	if handler == nil {
+		c.log.Debugf("Discarded text message from %v: %s", msg.Source, msg.Message)
+		return nil
+	}
+	handler(msg)
+	return nil
+}
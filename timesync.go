@@ -0,0 +1,60 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"time"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// TimeSync sets the clock of the devices at dest to t in t's location. The
+// destination is either the address of a single device,
+// bactype.RemoteBroadcast for every device on a remote network or
+// bactype.LocalBroadcast for every device on the local network.
+func (c *Client) TimeSync(dest bactype.Address, t time.Time) error {
+	dt := bactype.NewDateTime(t)
+	return c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+		return enc.TimeSync(dt)
+	})
+}
+
+// UTCTimeSync sets the clock of the devices at dest to t converted to UTC.
+// Devices use their own UTC offset to get the local time. See TimeSync for the
+// possible destinations.
+func (c *Client) UTCTimeSync(dest bactype.Address, t time.Time) error {
+	dt := bactype.NewDateTime(t.UTC())
+	return c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+		return enc.UTCTimeSync(dt)
+	})
+}
@@ -0,0 +1,223 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package tsm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxWindowSize is the largest number of segments that may be sent between two
+// segment acks.
+const MaxWindowSize = 127
+
+// ErrTooManySegments is returned by Segment when a response has more segments
+// than the receiver accepts. The segments received so far are dropped and the
+// sender should be sent an abort.
+var ErrTooManySegments = errors.New("response has more segments than accepted")
+
+// Segment is a segment of a segmented response
+type Segment struct {
+	Sequence uint8
+
+	// WindowSize is the window size proposed by the sender of the segment
+	WindowSize  uint8
+	MoreFollows bool
+	Data        []byte
+}
+
+// SegmentAck is the segment ack that must be sent to the sender of the
+// segments. A negative ack asks for the segments following Sequence to be sent
+// again.
+type SegmentAck struct {
+	Sequence   uint8
+	WindowSize uint8
+	Negative   bool
+}
+
+// reassembly puts the segments of a response back together. Sequence numbers
+// wrap around after 255 which the uint8 arithmetic takes care of.
+type reassembly struct {
+	windowSize uint8
+
+	// initial is the sequence number of the segment that started the current
+	// window and last is the last segment that was received in order.
+	initial uint8
+	last    uint8
+	data    []byte
+
+	// count is the number of segments received in order
+	count int
+}
+
+func newReassembly(seg Segment) (*reassembly, error) {
+	if seg.Sequence != 0 {
+		return nil, fmt.Errorf("segmented response started with segment %d", seg.Sequence)
+	}
+
+	window := seg.WindowSize
+	if window == 0 || window > MaxWindowSize {
+		window = MaxWindowSize
+	}
+	r := &reassembly{
+		windowSize: window,
+		data:       append([]byte(nil), seg.Data...),
+		count:      1,
+	}
+	return r, nil
+}
+
+// ack acknowledges every segment up to and including the last one
+func (r *reassembly) ack(negative bool) *SegmentAck {
+	return &SegmentAck{
+		Sequence:   r.last,
+		WindowSize: r.windowSize,
+		Negative:   negative,
+	}
+}
+
+// add adds the segment to the response. The returned ack is nil if no ack has
+// to be sent for the segment.
+func (r *reassembly) add(seg Segment) (ack *SegmentAck, done bool) {
+	if seg.Sequence != r.last+1 {
+		// Duplicate or out of order segment. Drop it and ask for everything
+		// after the last segment we have to be sent again.
+		r.initial = r.last
+		return r.ack(true), false
+	}
+
+	r.data = append(r.data, seg.Data...)
+	r.last = seg.Sequence
+	r.count++
+	if !seg.MoreFollows {
+		return r.ack(false), true
+	}
+	if seg.Sequence == r.initial+r.windowSize {
+		// The window is full, the sender waits for our ack
+		r.initial = seg.Sequence
+		return r.ack(false), false
+	}
+	return nil, false
+}
+
+// Segment adds a segment of the response to the transaction with the given
+// invoke id. The returned ack must be sent to the sender of the response when
+// it is not nil. Once the last segment has been added, the service data of all
+// segments is returned. A response with more than max segments fails with
+// ErrTooManySegments.
+func (t *TSM) Segment(id int, seg Segment, max int) (*SegmentAck, []byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s, ok := t.states[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("id %d is not receiving", id)
+	}
+
+	if s.segments == nil {
+		r, err := newReassembly(seg)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.segments = r
+		s.notifyProgress()
+		if !seg.MoreFollows {
+			s.segments = nil
+			return r.ack(false), r.data, nil
+		}
+		// The first segment is always acknowledged so that the sender learns
+		// our window size.
+		return r.ack(false), nil, nil
+	}
+
+	ack, done := s.segments.add(seg)
+	s.notifyProgress()
+	if s.segments.count > max {
+		s.segments = nil
+		return nil, nil, ErrTooManySegments
+	}
+	if !done {
+		return ack, nil, nil
+	}
+	data := s.segments.data
+	s.segments = nil
+	return ack, data, nil
+}
+
+// notifyProgress lets the receiver know that part of the response arrived
+func (s *state) notifyProgress() {
+	select {
+	case s.progress <- struct{}{}:
+	default:
+	}
+}
+
+// Ack passes a segment ack of a segmented request to the transaction with the
+// given invoke id. An ack that has not been received yet is replaced since the
+// latest ack supersedes it.
+func (t *TSM) Ack(id int, ack SegmentAck) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s, ok := t.states[id]
+	if !ok {
+		return fmt.Errorf("id %d is not sending segments", id)
+	}
+	select {
+	case <-s.acks:
+	default:
+	}
+	s.acks <- ack
+	return nil
+}
+
+// ReceiveAck waits for a segment ack of a segmented request. If the device
+// replies instead, for example because it aborted the request, the reply is
+// returned in place of the ack.
+func (t *TSM) ReceiveAck(id int, timeout time.Duration) (*SegmentAck, interface{}, error) {
+	t.mutex.Lock()
+	s, ok := t.states[id]
+	t.mutex.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("id %d is not sending segments", id)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ack := <-s.acks:
+		return &ack, nil, nil
+	case b := <-s.data:
+		return nil, b, nil
+	case <-timer.C:
+		return nil, nil, fmt.Errorf("Segment ack timed out (%v)", timeout)
+	}
+}
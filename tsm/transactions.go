@@ -52,6 +52,14 @@ type state struct {
 	state        int
 	requestTimer int
 	data         chan interface{}
+
+	// progress is signaled whenever a segment of the response is received
+	// and segments holds the segments received so far.
+	progress chan struct{}
+	segments *reassembly
+
+	// acks holds the latest segment ack of a segmented request
+	acks chan SegmentAck
 }
 
 // TSM is the transaction state manager. It handles passing data to other
@@ -96,15 +104,20 @@ func New(size int) *TSM {
 
 // Send data to invoked id
 func (t *TSM) Send(id int, b interface{}) error {
+	// The lock is held while sending so the channel cannot be closed by Put
 	t.mutex.Lock()
-	s, ok := t.states[id]
-	t.mutex.Unlock()
+	defer t.mutex.Unlock()
 
+	s, ok := t.states[id]
 	if !ok {
 		return fmt.Errorf("id %d is not receiving", id)
 	}
-	s.data <- b
-	return nil
+	select {
+	case s.data <- b:
+		return nil
+	default:
+		return fmt.Errorf("id %d has already been sent data", id)
+	}
 }
 
 // Receive attempts to receive a byte array from the invoked id. If a time out
@@ -118,17 +131,30 @@ func (t *TSM) Receive(id int, timeout time.Duration) (interface{}, error) {
 		return nil, fmt.Errorf("id %d is not sending", id)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
 	// Wait for data
-	select {
-	case b := <-s.data:
-		return b, nil
-	case <-ctx.Done():
-		return nil, fmt.Errorf("Receive timed out (%v)", timeout)
+	for {
+		select {
+		case b := <-s.data:
+			return b, nil
+		case <-s.progress:
+			// Part of a segmented response arrived, the timeout starts over
+			// while we wait for the next segment.
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			// A segmented response is started over when the request is
+			// sent again.
+			t.mutex.Lock()
+			s.segments = nil
+			t.mutex.Unlock()
+			return nil, fmt.Errorf("Receive timed out (%v)", timeout)
+		}
 	}
-
 }
 
 // ID returns the invoke id that was used to save the state of this connection.
@@ -151,8 +177,14 @@ func (t *TSM) ID(ctx context.Context) (int, error) {
 	s := t.pool.Get().(*state)
 	s.state = idle
 	s.requestTimer = 0 // TODO: apdu_timeout
-	s.data = make(chan interface{})
+	s.data = make(chan interface{}, 1)
+	s.progress = make(chan struct{}, 1)
+	s.acks = make(chan SegmentAck, 1)
+	s.segments = nil
+
+	t.mutex.Lock()
 	t.states[id] = s
+	t.mutex.Unlock()
 	return id, nil
 }
 
@@ -32,7 +32,9 @@ License.
 package tsm
 
 import (
+	"bytes"
 	"context"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -128,3 +130,67 @@ func TestDataTransaction(t *testing.T) {
 	}
 	t.Log(s)
 }
+
+func TestSegment(t *testing.T) {
+	tsm := New(1)
+	id, err := tsm.ID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A window of 2 segments is proposed. Segment 4 arrives before 3 and
+	// segment 2 is received twice.
+	tests := []struct {
+		seg  Segment
+		ack  *SegmentAck
+		done bool
+	}{
+		{Segment{Sequence: 0, WindowSize: 2, MoreFollows: true, Data: []byte{0}}, &SegmentAck{Sequence: 0, WindowSize: 2}, false},
+		{Segment{Sequence: 1, WindowSize: 2, MoreFollows: true, Data: []byte{1}}, nil, false},
+		{Segment{Sequence: 2, WindowSize: 2, MoreFollows: true, Data: []byte{2}}, &SegmentAck{Sequence: 2, WindowSize: 2}, false},
+		{Segment{Sequence: 2, WindowSize: 2, MoreFollows: true, Data: []byte{2}}, &SegmentAck{Sequence: 2, WindowSize: 2, Negative: true}, false},
+		{Segment{Sequence: 4, WindowSize: 2, MoreFollows: false, Data: []byte{4}}, &SegmentAck{Sequence: 2, WindowSize: 2, Negative: true}, false},
+		{Segment{Sequence: 3, WindowSize: 2, MoreFollows: true, Data: []byte{3}}, nil, false},
+		{Segment{Sequence: 4, WindowSize: 2, MoreFollows: false, Data: []byte{4}}, &SegmentAck{Sequence: 4, WindowSize: 2}, true},
+	}
+	for i, test := range tests {
+		ack, data, err := tsm.Segment(id, test.seg, 64)
+		if err != nil {
+			t.Fatalf("segment %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(ack, test.ack) {
+			t.Errorf("segment %d: expected ack %v, got %v", i, test.ack, ack)
+		}
+		if test.done != (data != nil) {
+			t.Fatalf("segment %d: expected done to be %t", i, test.done)
+		}
+		if test.done && !bytes.Equal(data, []byte{0, 1, 2, 3, 4}) {
+			t.Errorf("segments were not reassembled in order: %v", data)
+		}
+	}
+
+	// A new response has to start with the first segment
+	if _, _, err = tsm.Segment(id, Segment{Sequence: 1, MoreFollows: true}, 64); err == nil {
+		t.Error("A response starting with segment 1 should not be accepted")
+	}
+
+	// Segments past the limit fail the response
+	for i := 0; i < 3; i++ {
+		_, _, err = tsm.Segment(id, Segment{Sequence: uint8(i), MoreFollows: true}, 2)
+	}
+	if err != ErrTooManySegments {
+		t.Errorf("expected the third of 2 accepted segments to fail, got %v", err)
+	}
+
+	// Every segment restarts the timeout of the receiver
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(20 * time.Millisecond)
+			tsm.Segment(id, Segment{Sequence: uint8(i), MoreFollows: i < 2}, 64)
+		}
+		tsm.Send(id, "done")
+	}()
+	if _, err = tsm.Receive(id, 50*time.Millisecond); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,139 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// EventSummary is the alarm and event state of an object that has an active
+// event or unacknowledged transitions.
+type EventSummary struct {
+	Object     ObjectID
+	EventState EventState
+
+	// AcknowledgedTransitions has a bit for to-offnormal, to-fault and
+	// to-normal that is set when the transition has been acknowledged.
+	AcknowledgedTransitions BitString
+
+	// EventTimeStamps and EventPriorities are ordered to-offnormal, to-fault
+	// and to-normal.
+	EventTimeStamps [3]TimeStamp
+	NotifyType      NotifyType
+	EventEnable     BitString
+	EventPriorities [3]uint32
+}
+
+// GetEventInformation requests the event summaries of a device. Summaries are
+// returned in pages, LastObject is the last object of the previous page and
+// nil for the first page.
+type GetEventInformation struct {
+	LastObject *ObjectID
+}
+
+// GetEventInformationAck is a page of event summaries. MoreEvents is set when
+// there are summaries following the last one.
+type GetEventInformationAck struct {
+	Summaries  []EventSummary
+	MoreEvents bool
+}
+
+// AcknowledgeAlarm acknowledges the transition of an object to EventState. The
+// TimeStamp must match the time stamp of the event notification that is
+// acknowledged.
+type AcknowledgeAlarm struct {
+	ProcessID  uint32
+	Object     ObjectID
+	EventState EventState
+	TimeStamp  TimeStamp
+
+	// Source identifies who acknowledged the alarm, for example the name of
+	// the operator.
+	Source  string
+	AckTime TimeStamp
+}
+
+// AlarmSummary is an object in an alarm state as returned by get alarm summary
+type AlarmSummary struct {
+	Object                  ObjectID
+	AlarmState              EventState
+	AcknowledgedTransitions BitString
+}
+
+// AckFilter selects the enrollments returned by their acknowledgment state
+type AckFilter uint32
+
+const (
+	AckFilterAll      AckFilter = 0
+	AckFilterAcked    AckFilter = 1
+	AckFilterNotAcked AckFilter = 2
+)
+
+// EventStateFilter selects the enrollments returned by their event state.
+// EventStateFilterActive matches every state other than normal.
+type EventStateFilter uint32
+
+const (
+	EventStateFilterOffnormal EventStateFilter = 0
+	EventStateFilterFault     EventStateFilter = 1
+	EventStateFilterNormal    EventStateFilter = 2
+	EventStateFilterAll       EventStateFilter = 3
+	EventStateFilterActive    EventStateFilter = 4
+)
+
+// PriorityFilter matches enrollments with a priority from Min to Max
+type PriorityFilter struct {
+	Min uint8
+	Max uint8
+}
+
+// GetEnrollmentSummary requests the event enrollments of a device. Every
+// filter but the AckFilter is optional and only applied when set.
+type GetEnrollmentSummary struct {
+	AckFilter AckFilter
+
+	// Recipient and ProcessID limit the enrollments to the ones notifying
+	// that recipient process.
+	Recipient *Recipient
+	ProcessID uint32
+
+	EventState        *EventStateFilter
+	EventType         *EventType
+	Priority          *PriorityFilter
+	NotificationClass *uint32
+}
+
+// EnrollmentSummary is an event enrollment as returned by get enrollment
+// summary. NotificationClass is nil if the device did not send it.
+type EnrollmentSummary struct {
+	Object            ObjectID
+	EventType         EventType
+	EventState        EventState
+	Priority          uint8
+	NotificationClass *uint32
+}
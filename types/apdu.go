@@ -118,10 +118,19 @@ type APDU struct {
 	Service                   ServiceConfirmed
 	UnconfirmedService        ServiceUnconfirmed
 	Error                     struct {
-		Class uint32
-		Code  uint32
+		Class ErrorClass
+		Code  ErrorCode
 	}
 
+	// Reason is the reject or abort reason of reject and abort apdus
+	Reason uint8
+
+	// NegativeAck is only used by segment acks and asks for the segments
+	// following Sequence to be sent again. Server is set when a segment ack or
+	// abort is sent by the device that replies to the request.
+	NegativeAck bool
+	Server      bool
+
 	// This is the raw data passed based on the service
 	RawData []byte
 }
@@ -133,6 +142,7 @@ type PDUType uint8
 const (
 	ConfirmedServiceRequest   PDUType = 0
 	UnconfirmedServiceRequest PDUType = 0x10
+	SimpleAck                 PDUType = 0x20
 	ComplexAck                PDUType = 0x30
 	SegmentAck                PDUType = 0x40
 	Error                     PDUType = 0x50
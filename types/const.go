@@ -41,3 +41,13 @@ const (
 	WhoIsAll = -1
 	ArrayAll = 0xFFFFFFFF
 )
+
+const (
+	// NoPriority is used when writing to a property that is not commandable.
+	NoPriority = 0
+
+	// MinPriority and MaxPriority bound the command priorities of a
+	// commandable property. 1 is the highest priority.
+	MinPriority = 1
+	MaxPriority = 16
+)
@@ -0,0 +1,73 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// SubscribeCOV is used to subscribe to, or cancel a subscription to, the
+// change of value notifications of an object.
+type SubscribeCOV struct {
+	// ProcessID is chosen by the subscriber to identify the subscription
+	ProcessID uint32
+	Object    ObjectID
+
+	// Cancel removes an existing subscription. IssueConfirmed and Lifetime are
+	// ignored when cancelling.
+	Cancel         bool
+	IssueConfirmed bool
+
+	// Lifetime of the subscription in seconds. A lifetime of 0 never expires.
+	Lifetime uint32
+}
+
+// COVNotification is sent by a device when a subscribed object has changed.
+type COVNotification struct {
+	ProcessID uint32
+	Device    ObjectID
+	Object    ObjectID
+
+	// TimeRemaining is the time in seconds left in the subscription
+	TimeRemaining uint32
+	Values        []Property
+}
+
+// SubscribeCOVProperty is used to subscribe to the change of value
+// notifications of a single property of an object.
+type SubscribeCOVProperty struct {
+	SubscribeCOV
+
+	// Property and ArrayIndex are the monitored property
+	Property   uint32
+	ArrayIndex uint32
+
+	// COVIncrement is the minimum change of an analog property before a
+	// notification is sent. The device's own increment is used when nil.
+	COVIncrement *float32
+}
@@ -31,6 +31,8 @@ License.
 
 package types
 
+import "time"
+
 type DayOfWeek int
 
 const (
@@ -67,3 +69,51 @@ type Time struct {
 // UnspecifiedTime means that this time is triggered through out a period. An
 // example of this is 02:FF:FF:FF will trigger all through out 2 am
 const UnspecifiedTime = 0xFF
+
+// DateTime is a date followed by a time, for example the timestamp of a log
+// record.
+type DateTime struct {
+	Date Date
+	Time Time
+}
+
+// NewDate converts the date of t to a BACnet date.
+func NewDate(t time.Time) Date {
+	// Go starts the week on Sunday while BACnet starts it on Monday
+	day := DayOfWeek(t.Weekday())
+	if day == 0 {
+		day = Sunday
+	}
+	return Date{
+		Year:      t.Year(),
+		Month:     int(t.Month()),
+		Day:       t.Day(),
+		DayOfWeek: day,
+	}
+}
+
+// NewTime converts the time of day of t to a BACnet time.
+func NewTime(t time.Time) Time {
+	return Time{
+		Hour:        t.Hour(),
+		Minute:      t.Minute(),
+		Second:      t.Second(),
+		Millisecond: t.Nanosecond() / int(time.Millisecond),
+	}
+}
+
+// NewDateTime converts t to a BACnet date and time.
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{
+		Date: NewDate(t),
+		Time: NewTime(t),
+	}
+}
+
+// NewTimeStamp converts t to a BACnet time stamp holding a date and time.
+func NewTimeStamp(t time.Time) TimeStamp {
+	return TimeStamp{
+		Type:     TimeStampDateTime,
+		DateTime: NewDateTime(t),
+	}
+}
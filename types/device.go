@@ -0,0 +1,105 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// CommunicationState is the state a device is placed in by a device
+// communication control request.
+type CommunicationState uint32
+
+const (
+	EnableCommunication CommunicationState = 0
+	// DisableCommunication stops the device from responding to anything but
+	// device communication control and reinitialize device requests.
+	DisableCommunication CommunicationState = 1
+	// DisableInitiation stops the device from initiating messages, such as
+	// notifications, while it still responds to requests.
+	DisableInitiation CommunicationState = 2
+)
+
+// DeviceCommunicationControl is a request to enable or disable the
+// communication of a device.
+type DeviceCommunicationControl struct {
+	// Duration in minutes before communication is enabled again. A duration
+	// of 0 lasts until communication is enabled by another request.
+	Duration uint16
+	State    CommunicationState
+
+	// Password is left out of the request when empty
+	Password string
+}
+
+// ReinitializeState is the state a device is placed in by a reinitialize
+// device request.
+type ReinitializeState uint32
+
+const (
+	ColdStart       ReinitializeState = 0
+	WarmStart       ReinitializeState = 1
+	StartBackup     ReinitializeState = 2
+	EndBackup       ReinitializeState = 3
+	StartRestore    ReinitializeState = 4
+	EndRestore      ReinitializeState = 5
+	AbortRestore    ReinitializeState = 6
+	ActivateChanges ReinitializeState = 7
+)
+
+// ReinitializeDevice is a request to restart a device or to start or end a
+// backup or restore procedure.
+type ReinitializeDevice struct {
+	State ReinitializeState
+
+	// Password is left out of the request when empty
+	Password string
+}
+
+// MessagePriority is the priority of a text message
+type MessagePriority uint32
+
+const (
+	MessagePriorityNormal MessagePriority = 0
+	MessagePriorityUrgent MessagePriority = 1
+)
+
+// TextMessage is a message for an operator sent between devices.
+type TextMessage struct {
+	// Source is the device object of the device that sent the message
+	Source ObjectID
+
+	// Class is an optional message class, either a numeric class as a uint32
+	// or a string class. It is nil when not sent.
+	Class    interface{}
+	Priority MessagePriority
+	Message  string
+
+	// Addr is the address the message was received from. It is not sent.
+	Addr Address
+}
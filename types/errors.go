@@ -0,0 +1,458 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+import "fmt"
+
+// ErrorClass is the group of errors an ErrorCode belongs to.
+type ErrorClass uint32
+
+// ErrorCode identifies the reason a device returned an error.
+type ErrorCode uint32
+
+// PropertyError is the error class and code a device replies with when it
+// could not execute a request, or returns in place of the value of a property
+// it could not read.
+type PropertyError struct {
+	Class ErrorClass
+	Code  ErrorCode
+}
+
+func (e *PropertyError) Error() string {
+	return fmt.Sprintf("Error Class %v Code %v", e.Class, e.Code)
+}
+
+// Is reports whether target is a *PropertyError with the same error code.
+func (e *PropertyError) Is(target error) bool {
+	t, ok := target.(*PropertyError)
+	return ok && t.Code == e.Code
+}
+
+// RejectReason is the reason a device rejected a request because it could not
+// be parsed or was not understood.
+type RejectReason uint8
+
+// AbortReason is the reason a transaction was aborted.
+type AbortReason uint8
+
+const (
+	ErrorClassDevice        ErrorClass = 0
+	ErrorClassObject        ErrorClass = 1
+	ErrorClassProperty      ErrorClass = 2
+	ErrorClassResources     ErrorClass = 3
+	ErrorClassSecurity      ErrorClass = 4
+	ErrorClassServices      ErrorClass = 5
+	ErrorClassVT            ErrorClass = 6
+	ErrorClassCommunication ErrorClass = 7
+)
+
+var errorClassNames = map[ErrorClass]string{
+	ErrorClassDevice:        "device",
+	ErrorClassObject:        "object",
+	ErrorClassProperty:      "property",
+	ErrorClassResources:     "resources",
+	ErrorClassSecurity:      "security",
+	ErrorClassServices:      "services",
+	ErrorClassVT:            "vt",
+	ErrorClassCommunication: "communication",
+}
+
+func (v ErrorClass) String() string {
+	s, ok := errorClassNames[v]
+	if !ok {
+		return fmt.Sprintf("ErrorClass(%d)", v)
+	}
+	return s
+}
+
+const (
+	ErrorCodeOther                          ErrorCode = 0
+	ErrorCodeAuthenticationFailed           ErrorCode = 1
+	ErrorCodeConfigurationInProgress        ErrorCode = 2
+	ErrorCodeDeviceBusy                     ErrorCode = 3
+	ErrorCodeDynamicCreationNotSupported    ErrorCode = 4
+	ErrorCodeFileAccessDenied               ErrorCode = 5
+	ErrorCodeIncompatibleSecurityLevels     ErrorCode = 6
+	ErrorCodeInconsistentParameters         ErrorCode = 7
+	ErrorCodeInconsistentSelectionCriterion ErrorCode = 8
+	ErrorCodeInvalidDataType                ErrorCode = 9
+	ErrorCodeInvalidFileAccessMethod        ErrorCode = 10
+	ErrorCodeInvalidFileStartPosition       ErrorCode = 11
+	ErrorCodeInvalidOperatorName            ErrorCode = 12
+	ErrorCodeInvalidParameterDataType       ErrorCode = 13
+	ErrorCodeInvalidTimeStamp               ErrorCode = 14
+	ErrorCodeKeyGenerationError             ErrorCode = 15
+	ErrorCodeMissingRequiredParameter       ErrorCode = 16
+	ErrorCodeNoObjectsOfSpecifiedType       ErrorCode = 17
+	ErrorCodeNoSpaceForObject               ErrorCode = 18
+	ErrorCodeNoSpaceToAddListElement        ErrorCode = 19
+	ErrorCodeNoSpaceToWriteProperty         ErrorCode = 20
+	ErrorCodeNoVTSessionsAvailable          ErrorCode = 21
+	ErrorCodePropertyIsNotAList             ErrorCode = 22
+	ErrorCodeObjectDeletionNotPermitted     ErrorCode = 23
+	ErrorCodeObjectIdentifierAlreadyExists  ErrorCode = 24
+	ErrorCodeOperationalProblem             ErrorCode = 25
+	ErrorCodePasswordFailure                ErrorCode = 26
+	ErrorCodeReadAccessDenied               ErrorCode = 27
+	ErrorCodeSecurityNotSupported           ErrorCode = 28
+	ErrorCodeServiceRequestDenied           ErrorCode = 29
+	ErrorCodeTimeout                        ErrorCode = 30
+	ErrorCodeUnknownObject                  ErrorCode = 31
+	ErrorCodeUnknownProperty                ErrorCode = 32
+	// 33 is no longer used
+	ErrorCodeUnknownVTClass                     ErrorCode = 34
+	ErrorCodeUnknownVTSession                   ErrorCode = 35
+	ErrorCodeUnsupportedObjectType              ErrorCode = 36
+	ErrorCodeValueOutOfRange                    ErrorCode = 37
+	ErrorCodeVTSessionAlreadyClosed             ErrorCode = 38
+	ErrorCodeVTSessionTerminationFailure        ErrorCode = 39
+	ErrorCodeWriteAccessDenied                  ErrorCode = 40
+	ErrorCodeCharacterSetNotSupported           ErrorCode = 41
+	ErrorCodeInvalidArrayIndex                  ErrorCode = 42
+	ErrorCodeCOVSubscriptionFailed              ErrorCode = 43
+	ErrorCodeNotCOVProperty                     ErrorCode = 44
+	ErrorCodeOptionalFunctionalityNotSupported  ErrorCode = 45
+	ErrorCodeInvalidConfigurationData           ErrorCode = 46
+	ErrorCodeDatatypeNotSupported               ErrorCode = 47
+	ErrorCodeDuplicateName                      ErrorCode = 48
+	ErrorCodeDuplicateObjectID                  ErrorCode = 49
+	ErrorCodePropertyIsNotAnArray               ErrorCode = 50
+	ErrorCodeAbortBufferOverflow                ErrorCode = 51
+	ErrorCodeAbortInvalidAPDUInThisState        ErrorCode = 52
+	ErrorCodeAbortPreemptedByHigherPriorityTask ErrorCode = 53
+	ErrorCodeAbortSegmentationNotSupported      ErrorCode = 54
+	ErrorCodeAbortProprietary                   ErrorCode = 55
+	ErrorCodeAbortOther                         ErrorCode = 56
+	ErrorCodeInvalidTag                         ErrorCode = 57
+	ErrorCodeNetworkDown                        ErrorCode = 58
+	ErrorCodeRejectBufferOverflow               ErrorCode = 59
+	ErrorCodeRejectInconsistentParameters       ErrorCode = 60
+	ErrorCodeRejectInvalidParameterDataType     ErrorCode = 61
+	ErrorCodeRejectInvalidTag                   ErrorCode = 62
+	ErrorCodeRejectMissingRequiredParameter     ErrorCode = 63
+	ErrorCodeRejectParameterOutOfRange          ErrorCode = 64
+	ErrorCodeRejectTooManyArguments             ErrorCode = 65
+	ErrorCodeRejectUndefinedEnumeration         ErrorCode = 66
+	ErrorCodeRejectUnrecognizedService          ErrorCode = 67
+	ErrorCodeRejectProprietary                  ErrorCode = 68
+	ErrorCodeRejectOther                        ErrorCode = 69
+	ErrorCodeUnknownDevice                      ErrorCode = 70
+	ErrorCodeUnknownRoute                       ErrorCode = 71
+	ErrorCodeValueNotInitialized                ErrorCode = 72
+	ErrorCodeInvalidEventState                  ErrorCode = 73
+	ErrorCodeNoAlarmConfigured                  ErrorCode = 74
+	ErrorCodeLogBufferFull                      ErrorCode = 75
+	ErrorCodeLoggedValuePurged                  ErrorCode = 76
+	ErrorCodeNoPropertySpecified                ErrorCode = 77
+	ErrorCodeNotConfiguredForTriggeredLogging   ErrorCode = 78
+	ErrorCodeUnknownSubscription                ErrorCode = 79
+	ErrorCodeParameterOutOfRange                ErrorCode = 80
+	ErrorCodeListElementNotFound                ErrorCode = 81
+	ErrorCodeBusy                               ErrorCode = 82
+	ErrorCodeCommunicationDisabled              ErrorCode = 83
+	ErrorCodeSuccess                            ErrorCode = 84
+	ErrorCodeAccessDenied                       ErrorCode = 85
+	ErrorCodeBadDestinationAddress              ErrorCode = 86
+	ErrorCodeBadDestinationDeviceID             ErrorCode = 87
+	ErrorCodeBadSignature                       ErrorCode = 88
+	ErrorCodeBadSourceAddress                   ErrorCode = 89
+	ErrorCodeBadTimestamp                       ErrorCode = 90
+	ErrorCodeCannotUseKey                       ErrorCode = 91
+	ErrorCodeCannotVerifyMessageID              ErrorCode = 92
+	ErrorCodeCorrectKeyRevision                 ErrorCode = 93
+	ErrorCodeDestinationDeviceIDRequired        ErrorCode = 94
+	ErrorCodeDuplicateMessage                   ErrorCode = 95
+	ErrorCodeEncryptionNotConfigured            ErrorCode = 96
+	ErrorCodeEncryptionRequired                 ErrorCode = 97
+	ErrorCodeIncorrectKey                       ErrorCode = 98
+	ErrorCodeInvalidKeyData                     ErrorCode = 99
+	ErrorCodeKeyUpdateInProgress                ErrorCode = 100
+	ErrorCodeMalformedMessage                   ErrorCode = 101
+	ErrorCodeNotKeyServer                       ErrorCode = 102
+	ErrorCodeSecurityNotConfigured              ErrorCode = 103
+	ErrorCodeSourceSecurityRequired             ErrorCode = 104
+	ErrorCodeTooManyKeys                        ErrorCode = 105
+	ErrorCodeUnknownAuthenticationType          ErrorCode = 106
+	ErrorCodeUnknownKey                         ErrorCode = 107
+	ErrorCodeUnknownKeyRevision                 ErrorCode = 108
+	ErrorCodeUnknownSourceMessage               ErrorCode = 109
+	ErrorCodeNotRouterToDNET                    ErrorCode = 110
+	ErrorCodeRouterBusy                         ErrorCode = 111
+	ErrorCodeUnknownNetworkMessage              ErrorCode = 112
+	ErrorCodeMessageTooLong                     ErrorCode = 113
+	ErrorCodeSecurityError                      ErrorCode = 114
+	ErrorCodeAddressingError                    ErrorCode = 115
+	ErrorCodeWriteBDTFailed                     ErrorCode = 116
+	ErrorCodeReadBDTFailed                      ErrorCode = 117
+	ErrorCodeRegisterForeignDeviceFailed        ErrorCode = 118
+	ErrorCodeReadFDTFailed                      ErrorCode = 119
+	ErrorCodeDeleteFDTEntryFailed               ErrorCode = 120
+	ErrorCodeDistributeBroadcastFailed          ErrorCode = 121
+	ErrorCodeUnknownFileSize                    ErrorCode = 122
+	ErrorCodeAbortAPDUTooLong                   ErrorCode = 123
+	ErrorCodeAbortApplicationExceededReplyTime  ErrorCode = 124
+	ErrorCodeAbortOutOfResources                ErrorCode = 125
+	ErrorCodeAbortTSMTimeout                    ErrorCode = 126
+	ErrorCodeAbortWindowSizeOutOfRange          ErrorCode = 127
+	ErrorCodeFileFull                           ErrorCode = 128
+	ErrorCodeInconsistentConfiguration          ErrorCode = 129
+	ErrorCodeInconsistentObjectType             ErrorCode = 130
+	ErrorCodeInternalError                      ErrorCode = 131
+	ErrorCodeNotConfigured                      ErrorCode = 132
+	ErrorCodeOutOfMemory                        ErrorCode = 133
+	ErrorCodeValueTooLong                       ErrorCode = 134
+	ErrorCodeAbortInsufficientSecurity          ErrorCode = 135
+	ErrorCodeAbortSecurityError                 ErrorCode = 136
+	ErrorCodeDuplicateEntry                     ErrorCode = 137
+	ErrorCodeInvalidValueInThisState            ErrorCode = 138
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrorCodeOther:                              "other",
+	ErrorCodeAuthenticationFailed:               "authentication-failed",
+	ErrorCodeConfigurationInProgress:            "configuration-in-progress",
+	ErrorCodeDeviceBusy:                         "device-busy",
+	ErrorCodeDynamicCreationNotSupported:        "dynamic-creation-not-supported",
+	ErrorCodeFileAccessDenied:                   "file-access-denied",
+	ErrorCodeIncompatibleSecurityLevels:         "incompatible-security-levels",
+	ErrorCodeInconsistentParameters:             "inconsistent-parameters",
+	ErrorCodeInconsistentSelectionCriterion:     "inconsistent-selection-criterion",
+	ErrorCodeInvalidDataType:                    "invalid-data-type",
+	ErrorCodeInvalidFileAccessMethod:            "invalid-file-access-method",
+	ErrorCodeInvalidFileStartPosition:           "invalid-file-start-position",
+	ErrorCodeInvalidOperatorName:                "invalid-operator-name",
+	ErrorCodeInvalidParameterDataType:           "invalid-parameter-data-type",
+	ErrorCodeInvalidTimeStamp:                   "invalid-time-stamp",
+	ErrorCodeKeyGenerationError:                 "key-generation-error",
+	ErrorCodeMissingRequiredParameter:           "missing-required-parameter",
+	ErrorCodeNoObjectsOfSpecifiedType:           "no-objects-of-specified-type",
+	ErrorCodeNoSpaceForObject:                   "no-space-for-object",
+	ErrorCodeNoSpaceToAddListElement:            "no-space-to-add-list-element",
+	ErrorCodeNoSpaceToWriteProperty:             "no-space-to-write-property",
+	ErrorCodeNoVTSessionsAvailable:              "no-vt-sessions-available",
+	ErrorCodePropertyIsNotAList:                 "property-is-not-a-list",
+	ErrorCodeObjectDeletionNotPermitted:         "object-deletion-not-permitted",
+	ErrorCodeObjectIdentifierAlreadyExists:      "object-identifier-already-exists",
+	ErrorCodeOperationalProblem:                 "operational-problem",
+	ErrorCodePasswordFailure:                    "password-failure",
+	ErrorCodeReadAccessDenied:                   "read-access-denied",
+	ErrorCodeSecurityNotSupported:               "security-not-supported",
+	ErrorCodeServiceRequestDenied:               "service-request-denied",
+	ErrorCodeTimeout:                            "timeout",
+	ErrorCodeUnknownObject:                      "unknown-object",
+	ErrorCodeUnknownProperty:                    "unknown-property",
+	ErrorCodeUnknownVTClass:                     "unknown-vt-class",
+	ErrorCodeUnknownVTSession:                   "unknown-vt-session",
+	ErrorCodeUnsupportedObjectType:              "unsupported-object-type",
+	ErrorCodeValueOutOfRange:                    "value-out-of-range",
+	ErrorCodeVTSessionAlreadyClosed:             "vt-session-already-closed",
+	ErrorCodeVTSessionTerminationFailure:        "vt-session-termination-failure",
+	ErrorCodeWriteAccessDenied:                  "write-access-denied",
+	ErrorCodeCharacterSetNotSupported:           "character-set-not-supported",
+	ErrorCodeInvalidArrayIndex:                  "invalid-array-index",
+	ErrorCodeCOVSubscriptionFailed:              "cov-subscription-failed",
+	ErrorCodeNotCOVProperty:                     "not-cov-property",
+	ErrorCodeOptionalFunctionalityNotSupported:  "optional-functionality-not-supported",
+	ErrorCodeInvalidConfigurationData:           "invalid-configuration-data",
+	ErrorCodeDatatypeNotSupported:               "datatype-not-supported",
+	ErrorCodeDuplicateName:                      "duplicate-name",
+	ErrorCodeDuplicateObjectID:                  "duplicate-object-id",
+	ErrorCodePropertyIsNotAnArray:               "property-is-not-an-array",
+	ErrorCodeAbortBufferOverflow:                "abort-buffer-overflow",
+	ErrorCodeAbortInvalidAPDUInThisState:        "abort-invalid-apdu-in-this-state",
+	ErrorCodeAbortPreemptedByHigherPriorityTask: "abort-preempted-by-higher-priority-task",
+	ErrorCodeAbortSegmentationNotSupported:      "abort-segmentation-not-supported",
+	ErrorCodeAbortProprietary:                   "abort-proprietary",
+	ErrorCodeAbortOther:                         "abort-other",
+	ErrorCodeInvalidTag:                         "invalid-tag",
+	ErrorCodeNetworkDown:                        "network-down",
+	ErrorCodeRejectBufferOverflow:               "reject-buffer-overflow",
+	ErrorCodeRejectInconsistentParameters:       "reject-inconsistent-parameters",
+	ErrorCodeRejectInvalidParameterDataType:     "reject-invalid-parameter-data-type",
+	ErrorCodeRejectInvalidTag:                   "reject-invalid-tag",
+	ErrorCodeRejectMissingRequiredParameter:     "reject-missing-required-parameter",
+	ErrorCodeRejectParameterOutOfRange:          "reject-parameter-out-of-range",
+	ErrorCodeRejectTooManyArguments:             "reject-too-many-arguments",
+	ErrorCodeRejectUndefinedEnumeration:         "reject-undefined-enumeration",
+	ErrorCodeRejectUnrecognizedService:          "reject-unrecognized-service",
+	ErrorCodeRejectProprietary:                  "reject-proprietary",
+	ErrorCodeRejectOther:                        "reject-other",
+	ErrorCodeUnknownDevice:                      "unknown-device",
+	ErrorCodeUnknownRoute:                       "unknown-route",
+	ErrorCodeValueNotInitialized:                "value-not-initialized",
+	ErrorCodeInvalidEventState:                  "invalid-event-state",
+	ErrorCodeNoAlarmConfigured:                  "no-alarm-configured",
+	ErrorCodeLogBufferFull:                      "log-buffer-full",
+	ErrorCodeLoggedValuePurged:                  "logged-value-purged",
+	ErrorCodeNoPropertySpecified:                "no-property-specified",
+	ErrorCodeNotConfiguredForTriggeredLogging:   "not-configured-for-triggered-logging",
+	ErrorCodeUnknownSubscription:                "unknown-subscription",
+	ErrorCodeParameterOutOfRange:                "parameter-out-of-range",
+	ErrorCodeListElementNotFound:                "list-element-not-found",
+	ErrorCodeBusy:                               "busy",
+	ErrorCodeCommunicationDisabled:              "communication-disabled",
+	ErrorCodeSuccess:                            "success",
+	ErrorCodeAccessDenied:                       "access-denied",
+	ErrorCodeBadDestinationAddress:              "bad-destination-address",
+	ErrorCodeBadDestinationDeviceID:             "bad-destination-device-id",
+	ErrorCodeBadSignature:                       "bad-signature",
+	ErrorCodeBadSourceAddress:                   "bad-source-address",
+	ErrorCodeBadTimestamp:                       "bad-timestamp",
+	ErrorCodeCannotUseKey:                       "cannot-use-key",
+	ErrorCodeCannotVerifyMessageID:              "cannot-verify-message-id",
+	ErrorCodeCorrectKeyRevision:                 "correct-key-revision",
+	ErrorCodeDestinationDeviceIDRequired:        "destination-device-id-required",
+	ErrorCodeDuplicateMessage:                   "duplicate-message",
+	ErrorCodeEncryptionNotConfigured:            "encryption-not-configured",
+	ErrorCodeEncryptionRequired:                 "encryption-required",
+	ErrorCodeIncorrectKey:                       "incorrect-key",
+	ErrorCodeInvalidKeyData:                     "invalid-key-data",
+	ErrorCodeKeyUpdateInProgress:                "key-update-in-progress",
+	ErrorCodeMalformedMessage:                   "malformed-message",
+	ErrorCodeNotKeyServer:                       "not-key-server",
+	ErrorCodeSecurityNotConfigured:              "security-not-configured",
+	ErrorCodeSourceSecurityRequired:             "source-security-required",
+	ErrorCodeTooManyKeys:                        "too-many-keys",
+	ErrorCodeUnknownAuthenticationType:          "unknown-authentication-type",
+	ErrorCodeUnknownKey:                         "unknown-key",
+	ErrorCodeUnknownKeyRevision:                 "unknown-key-revision",
+	ErrorCodeUnknownSourceMessage:               "unknown-source-message",
+	ErrorCodeNotRouterToDNET:                    "not-router-to-dnet",
+	ErrorCodeRouterBusy:                         "router-busy",
+	ErrorCodeUnknownNetworkMessage:              "unknown-network-message",
+	ErrorCodeMessageTooLong:                     "message-too-long",
+	ErrorCodeSecurityError:                      "security-error",
+	ErrorCodeAddressingError:                    "addressing-error",
+	ErrorCodeWriteBDTFailed:                     "write-bdt-failed",
+	ErrorCodeReadBDTFailed:                      "read-bdt-failed",
+	ErrorCodeRegisterForeignDeviceFailed:        "register-foreign-device-failed",
+	ErrorCodeReadFDTFailed:                      "read-fdt-failed",
+	ErrorCodeDeleteFDTEntryFailed:               "delete-fdt-entry-failed",
+	ErrorCodeDistributeBroadcastFailed:          "distribute-broadcast-failed",
+	ErrorCodeUnknownFileSize:                    "unknown-file-size",
+	ErrorCodeAbortAPDUTooLong:                   "abort-apdu-too-long",
+	ErrorCodeAbortApplicationExceededReplyTime:  "abort-application-exceeded-reply-time",
+	ErrorCodeAbortOutOfResources:                "abort-out-of-resources",
+	ErrorCodeAbortTSMTimeout:                    "abort-tsm-timeout",
+	ErrorCodeAbortWindowSizeOutOfRange:          "abort-window-size-out-of-range",
+	ErrorCodeFileFull:                           "file-full",
+	ErrorCodeInconsistentConfiguration:          "inconsistent-configuration",
+	ErrorCodeInconsistentObjectType:             "inconsistent-object-type",
+	ErrorCodeInternalError:                      "internal-error",
+	ErrorCodeNotConfigured:                      "not-configured",
+	ErrorCodeOutOfMemory:                        "out-of-memory",
+	ErrorCodeValueTooLong:                       "value-too-long",
+	ErrorCodeAbortInsufficientSecurity:          "abort-insufficient-security",
+	ErrorCodeAbortSecurityError:                 "abort-security-error",
+	ErrorCodeDuplicateEntry:                     "duplicate-entry",
+	ErrorCodeInvalidValueInThisState:            "invalid-value-in-this-state",
+}
+
+func (v ErrorCode) String() string {
+	s, ok := errorCodeNames[v]
+	if !ok {
+		return fmt.Sprintf("ErrorCode(%d)", v)
+	}
+	return s
+}
+
+const (
+	RejectReasonOther                    RejectReason = 0
+	RejectReasonBufferOverflow           RejectReason = 1
+	RejectReasonInconsistentParameters   RejectReason = 2
+	RejectReasonInvalidParameterDataType RejectReason = 3
+	RejectReasonInvalidTag               RejectReason = 4
+	RejectReasonMissingRequiredParameter RejectReason = 5
+	RejectReasonParameterOutOfRange      RejectReason = 6
+	RejectReasonTooManyArguments         RejectReason = 7
+	RejectReasonUndefinedEnumeration     RejectReason = 8
+	RejectReasonUnrecognizedService      RejectReason = 9
+)
+
+var rejectReasonNames = map[RejectReason]string{
+	RejectReasonOther:                    "other",
+	RejectReasonBufferOverflow:           "buffer-overflow",
+	RejectReasonInconsistentParameters:   "inconsistent-parameters",
+	RejectReasonInvalidParameterDataType: "invalid-parameter-data-type",
+	RejectReasonInvalidTag:               "invalid-tag",
+	RejectReasonMissingRequiredParameter: "missing-required-parameter",
+	RejectReasonParameterOutOfRange:      "parameter-out-of-range",
+	RejectReasonTooManyArguments:         "too-many-arguments",
+	RejectReasonUndefinedEnumeration:     "undefined-enumeration",
+	RejectReasonUnrecognizedService:      "unrecognized-service",
+}
+
+func (v RejectReason) String() string {
+	s, ok := rejectReasonNames[v]
+	if !ok {
+		return fmt.Sprintf("RejectReason(%d)", v)
+	}
+	return s
+}
+
+const (
+	AbortReasonOther                         AbortReason = 0
+	AbortReasonBufferOverflow                AbortReason = 1
+	AbortReasonInvalidAPDUInThisState        AbortReason = 2
+	AbortReasonPreemptedByHigherPriorityTask AbortReason = 3
+	AbortReasonSegmentationNotSupported      AbortReason = 4
+	AbortReasonSecurityError                 AbortReason = 5
+	AbortReasonInsufficientSecurity          AbortReason = 6
+	AbortReasonWindowSizeOutOfRange          AbortReason = 7
+	AbortReasonApplicationExceededReplyTime  AbortReason = 8
+	AbortReasonOutOfResources                AbortReason = 9
+	AbortReasonTSMTimeout                    AbortReason = 10
+	AbortReasonAPDUTooLong                   AbortReason = 11
+)
+
+var abortReasonNames = map[AbortReason]string{
+	AbortReasonOther:                         "other",
+	AbortReasonBufferOverflow:                "buffer-overflow",
+	AbortReasonInvalidAPDUInThisState:        "invalid-apdu-in-this-state",
+	AbortReasonPreemptedByHigherPriorityTask: "preempted-by-higher-priority-task",
+	AbortReasonSegmentationNotSupported:      "segmentation-not-supported",
+	AbortReasonSecurityError:                 "security-error",
+	AbortReasonInsufficientSecurity:          "insufficient-security",
+	AbortReasonWindowSizeOutOfRange:          "window-size-out-of-range",
+	AbortReasonApplicationExceededReplyTime:  "application-exceeded-reply-time",
+	AbortReasonOutOfResources:                "out-of-resources",
+	AbortReasonTSMTimeout:                    "tsm-timeout",
+	AbortReasonAPDUTooLong:                   "apdu-too-long",
+}
+
+func (v AbortReason) String() string {
+	s, ok := abortReasonNames[v]
+	if !ok {
+		return fmt.Sprintf("AbortReason(%d)", v)
+	}
+	return s
+}
@@ -0,0 +1,178 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// EventState is the state of an object's event algorithm
+type EventState uint32
+
+const (
+	EventStateNormal          EventState = 0
+	EventStateFault           EventState = 1
+	EventStateOffnormal       EventState = 2
+	EventStateHighLimit       EventState = 3
+	EventStateLowLimit        EventState = 4
+	EventStateLifeSafetyAlarm EventState = 5
+)
+
+// EventType is the event algorithm that generated a notification
+type EventType uint32
+
+const (
+	EventChangeOfBitstring  EventType = 0
+	EventChangeOfState      EventType = 1
+	EventChangeOfValue      EventType = 2
+	EventCommandFailure     EventType = 3
+	EventFloatingLimit      EventType = 4
+	EventOutOfRange         EventType = 5
+	EventChangeOfLifeSafety EventType = 8
+	EventExtended           EventType = 9
+	EventBufferReady        EventType = 10
+	EventUnsignedRange      EventType = 11
+)
+
+// NotifyType tells whether a notification is an alarm, an event or an
+// acknowledgment of a previous notification.
+type NotifyType uint32
+
+const (
+	NotifyAlarm           NotifyType = 0
+	NotifyEvent           NotifyType = 1
+	NotifyAckNotification NotifyType = 2
+)
+
+// TimeStampType selects which field of a TimeStamp is used
+type TimeStampType uint8
+
+const (
+	TimeStampTime           TimeStampType = 0
+	TimeStampSequenceNumber TimeStampType = 1
+	TimeStampDateTime       TimeStampType = 2
+)
+
+// TimeStamp is either a time, a sequence number or a date and time, as chosen
+// by Type.
+type TimeStamp struct {
+	Type           TimeStampType
+	Time           Time
+	SequenceNumber uint32
+	DateTime       DateTime
+}
+
+// EventNotification is sent by a device when an event occurs, for example when
+// an object enters or leaves an alarm state.
+type EventNotification struct {
+	ProcessID         uint32
+	Device            ObjectID
+	Object            ObjectID
+	TimeStamp         TimeStamp
+	NotificationClass uint32
+	Priority          uint8
+	EventType         EventType
+
+	// MessageText is optional and left empty when not sent
+	MessageText string
+	NotifyType  NotifyType
+
+	// AckRequired, FromState and EventValues are not sent in ack
+	// notifications.
+	AckRequired bool
+	FromState   EventState
+	ToState     EventState
+
+	// EventValues holds the notification parameters of the event type. It is
+	// one of the *Parameters types of this package or nil.
+	EventValues interface{}
+}
+
+// ChangeOfBitstringParameters are the values of a change of bitstring event
+type ChangeOfBitstringParameters struct {
+	ReferencedBitstring BitString
+	StatusFlags         BitString
+}
+
+// ChangeOfStateParameters are the values of a change of state event
+type ChangeOfStateParameters struct {
+	NewState    PropertyState
+	StatusFlags BitString
+}
+
+// PropertyState is a state of a property such as a binary value or an event
+// state. Type is the kind of state, for example 1 for a binary value, and
+// Value is the enumerated state. Boolean states are 0 or 1.
+type PropertyState struct {
+	Type  uint8
+	Value uint32
+}
+
+// ChangeOfValueParameters are the values of a change of value event. NewValue
+// is either the changed bits as a BitString or the changed value as a float32.
+type ChangeOfValueParameters struct {
+	NewValue    interface{}
+	StatusFlags BitString
+}
+
+// CommandFailureParameters are the values of a command failure event
+type CommandFailureParameters struct {
+	CommandValue  interface{}
+	StatusFlags   BitString
+	FeedbackValue interface{}
+}
+
+// FloatingLimitParameters are the values of a floating limit event
+type FloatingLimitParameters struct {
+	ReferenceValue float32
+	StatusFlags    BitString
+	SetpointValue  float32
+	ErrorLimit     float32
+}
+
+// OutOfRangeParameters are the values of an out of range event
+type OutOfRangeParameters struct {
+	ExceedingValue float32
+	StatusFlags    BitString
+	Deadband       float32
+	ExceededLimit  float32
+}
+
+// UnsignedRangeParameters are the values of an unsigned range event
+type UnsignedRangeParameters struct {
+	ExceedingValue uint32
+	StatusFlags    BitString
+	ExceededLimit  uint32
+}
+
+// RawParameters holds the encoded values of event types that are not decoded.
+// Tag is the event type's choice within the notification parameters.
+type RawParameters struct {
+	Tag  uint8
+	Data []byte
+}
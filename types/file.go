@@ -0,0 +1,73 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// AtomicReadFile is a request to read part of a File object. Either stream
+// access, where Start and Count are in octets, or record access, where they
+// are in records, is used.
+type AtomicReadFile struct {
+	File   ObjectID
+	Record bool
+	Start  int32
+	Count  uint32
+}
+
+// AtomicReadFileAck is the data read from a File object.
+type AtomicReadFileAck struct {
+	EndOfFile bool
+	Record    bool
+	Start     int32
+
+	// Data is set when using stream access and Records when using record
+	// access.
+	Data    []byte
+	Records [][]byte
+}
+
+// AtomicWriteFile is a request to write part of a File object. A Start of -1
+// appends to the end of the file.
+type AtomicWriteFile struct {
+	File   ObjectID
+	Record bool
+	Start  int32
+
+	// Data is written when using stream access and Records when using record
+	// access.
+	Data    []byte
+	Records [][]byte
+}
+
+// AtomicWriteFileAck holds the octet, or record, the data was written at.
+type AtomicWriteFileAck struct {
+	Record bool
+	Start  int32
+}
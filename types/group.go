@@ -0,0 +1,59 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// WriteGroup writes values to the Channel objects of a control group in every
+// device that receives it.
+type WriteGroup struct {
+	Group uint32
+
+	// Priority is the write priority from 1 to 16 used for every change that
+	// does not have an overriding priority.
+	Priority uint8
+	Changes  []GroupChannelValue
+
+	// InhibitDelay is optional and not sent when nil
+	InhibitDelay *bool
+}
+
+// GroupChannelValue is a value written to a channel of a control group
+type GroupChannelValue struct {
+	Channel uint16
+
+	// OverridingPriority is used instead of the priority of the request
+	// unless it is NoPriority.
+	OverridingPriority uint8
+
+	// Value is application data, for example nil to relinquish the channel,
+	// a float32 or an Enumerated.
+	Value interface{}
+}
@@ -0,0 +1,78 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// ListElement is used to add elements to, or remove elements from, a list
+// property.
+type ListElement struct {
+	Object     ObjectID
+	Property   uint32
+	ArrayIndex uint32
+	Elements   []interface{}
+}
+
+// Destination is an entry of the Recipient_List of a Notification Class.
+type Destination struct {
+	// ValidDays has a bit for every day of the week starting with Monday
+	ValidDays BitString
+	FromTime  Time
+	ToTime    Time
+	Recipient Recipient
+	ProcessID uint32
+
+	IssueConfirmed bool
+
+	// Transitions has a bit for to-offnormal, to-fault and to-normal
+	Transitions BitString
+}
+
+// Recipient of a notification, either a device or an address. Only one of
+// them should be set.
+type Recipient struct {
+	Device  *ObjectID
+	Address *Address
+}
+
+// DateRange is an entry of a Date_List matching every day from Start to End.
+type DateRange struct {
+	Start Date
+	End   Date
+}
+
+// WeekNDay is an entry of a Date_List matching a day of a week of a month.
+// Each field may be UnspecifiedTime to match any value. WeekOfMonth 1 is days
+// 1 to 7, 2 is days 8 to 14 and so on while 6 is the last 7 days of the month.
+type WeekNDay struct {
+	Month       uint8
+	WeekOfMonth uint8
+	DayOfWeek   DayOfWeek
+}
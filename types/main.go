@@ -57,6 +57,21 @@ type Property struct {
 	Type       uint32
 	ArrayIndex uint32
 	Data       interface{}
+
+	// Priority is the command priority used when writing the property. It is
+	// ignored on reads.
+	Priority uint8 `json:",omitempty"`
+
+	// Error is set instead of Data when a device could not read this property
+	// as part of a read property multiple request.
+	Error *PropertyError `json:",omitempty"`
+}
+
+// ObjectPropertyReference refers to a single property of an object.
+type ObjectPropertyReference struct {
+	Object     ObjectID
+	Property   uint32
+	ArrayIndex uint32
 }
 
 type ReadPropertyData struct {
@@ -66,12 +81,29 @@ type ReadPropertyData struct {
 	ErrorCode  uint8
 }
 
+// WritePropertyData is used to write a single property. Only the first
+// property in Object is written.
+type WritePropertyData struct {
+	Object Object
+
+	// Priority is the command priority of the write. It is only meaningful for
+	// commandable properties and is left out of the request when set to
+	// NoPriority.
+	Priority uint8
+}
+
 type ReadMultipleProperty struct {
 	Objects    []Object
 	ErrorClass uint8
 	ErrorCode  uint8
 }
 
+// WriteMultipleProperty holds the values that are written to one or more
+// properties of one or more objects in a single request.
+type WriteMultipleProperty struct {
+	Objects []Object
+}
+
 type Address struct {
 	Net    uint16
 	Len    uint8
@@ -89,6 +121,29 @@ type Device struct {
 	Vendor       uint32
 	Addr         Address
 	Objects      ObjectMap
+
+	// MaxSegments is the Max_Segments_Accepted of the device. When it is 0,
+	// the client reads it, along with the segmentation if that is
+	// SegmentationBoth, from the device before segmenting a request since
+	// neither is known for devices that are built by hand. A device that does
+	// not have the property accepts any number of segments.
+	MaxSegments uint
+}
+
+// Segmentation supported by a device as announced in its I-Am
+const (
+	SegmentationBoth     Enumerated = 0
+	SegmentationTransmit Enumerated = 1
+	SegmentationReceive  Enumerated = 2
+	NoSegmentation       Enumerated = 3
+)
+
+// AcceptsSegmentedRequests checks to see if the device is able to receive
+// segmented requests. Since SegmentationBoth is the zero value, it is only
+// meaningful when Segmentation was set from the I-Am or the
+// Segmentation_Supported of the device.
+func (d Device) AcceptsSegmentedRequests() bool {
+	return d.Segmentation == SegmentationBoth || d.Segmentation == SegmentationReceive
 }
 
 type IAm struct {
@@ -101,6 +156,22 @@ type IAm struct {
 
 const broadcastNetwork uint16 = 0xFFFF
 
+// LocalBroadcast returns the address of every device on the local network.
+func LocalBroadcast() Address {
+	return Address{}
+}
+
+// RemoteBroadcast returns the address of every device on the given network.
+// The message is broadcast locally for the router of that network to forward.
+func RemoteBroadcast(net uint16) Address {
+	return Address{Net: net}
+}
+
+// GlobalBroadcast returns the address of every device on every network.
+func GlobalBroadcast() Address {
+	return Address{Net: broadcastNetwork}
+}
+
 // IsBroadcast returns if the address is a broadcast address
 func (a *Address) IsBroadcast() bool {
 	if a.Net == broadcastNetwork || a.MacLen == 0 {
@@ -194,20 +265,24 @@ func (om ObjectMap) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
-func (om ObjectMap) UnmarshalJSON(data []byte) error {
+func (om *ObjectMap) UnmarshalJSON(data []byte) error {
 	m := make(map[string]map[ObjectInstance]Object, 0)
 	err := json.Unmarshal(data, &m)
 	if err != nil {
 		return err
 	}
 
+	// The map is nil when decoding a device
+	if *om == nil {
+		*om = make(ObjectMap)
+	}
 	for t, sub := range m {
 		key := GetType(t)
-		if om[key] == nil {
-			om[key] = make(map[ObjectInstance]Object)
+		if (*om)[key] == nil {
+			(*om)[key] = make(map[ObjectInstance]Object)
 		}
 		for inst, obj := range sub {
-			om[key][inst] = obj
+			(*om)[key][inst] = obj
 		}
 	}
 	return nil
@@ -223,3 +298,46 @@ func (dev Device) ObjectSlice() []Object {
 	}
 	return objs
 }
+
+// BitString is a list of bits where index 0 is the first bit in the string.
+type BitString []bool
+
+// Bit returns the value of the bit at index i. Bits that are past the length of
+// the string are false.
+func (b BitString) Bit(i int) bool {
+	if i < 0 || i >= len(b) {
+		return false
+	}
+	return b[i]
+}
+
+// CreateObject is a request to create an object in a device. The device
+// chooses the instance of the new object unless ID is set.
+type CreateObject struct {
+	Type ObjectType
+	ID   *ObjectID
+
+	// Properties are the initial values of the object
+	Properties []Property
+}
+
+// WhoHas is a request to find the devices that contain an object, either by
+// its identifier or by its name. Only devices with an instance between Low
+// and High reply; use WhoIsAll for both to ask every device.
+type WhoHas struct {
+	Low  int32
+	High int32
+
+	// Object is used when set, otherwise the object is found by Name
+	Object *ObjectID
+	Name   string
+}
+
+// IHave is the reply of a device that contains the object of a who has
+// request.
+type IHave struct {
+	Device ObjectID
+	Object ObjectID
+	Name   string
+	Addr   Address
+}
@@ -31,3 +31,47 @@ func TestMarshal(t *testing.T) {
 	}
 
 }
+
+// TestMarshalDevice tests decoding devices saved by discover, where the object
+// map starts out nil.
+func TestMarshalDevice(t *testing.T) {
+	dev := Device{
+		ID:      ObjectID{Type: DeviceType, Instance: 1234},
+		Objects: ObjectMap{AnalogValue: {1: Object{Name: "Pizza Oven"}}},
+	}
+	b, err := json.Marshal([]Device{dev})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []Device
+	if err = json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || !reflect.DeepEqual(dev.Objects, out[0].Objects) {
+		t.Fatalf("Expected %v, got %v", dev, out)
+	}
+}
+
+// TestMarshalPropertyError tests that properties that could not be read keep
+// their error when saved.
+func TestMarshalPropertyError(t *testing.T) {
+	obj := Object{
+		Name: "Pizza Oven",
+		Properties: []Property{
+			{Type: 28, ArrayIndex: ArrayAll, Error: &PropertyError{Class: ErrorClassProperty, Code: ErrorCodeUnknownProperty}},
+		},
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out Object
+	if err = json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(obj, out) {
+		t.Fatalf("Expected %v, got %v", obj, out)
+	}
+}
@@ -20,9 +20,12 @@ const (
 	DeviceType        ObjectType = 8
 	File              ObjectType = 10
 	MultiStateInput   ObjectType = 13
+	MultiStateOutput  ObjectType = 14
 	NotificationClass ObjectType = 15
 	MultiStateValue   ObjectType = 19
 	TrendLog          ObjectType = 20
+	EventLog          ObjectType = 25
+	Channel           ObjectType = 53
 	CharacterString   ObjectType = 40
 )
 
@@ -38,7 +41,10 @@ const (
 	NotificationClassStr = "Notification Class"
 	MultiStateValueStr   = "Multi-State Value"
 	MultiStateInputStr   = "Multi-State Input"
+	MultiStateOutputStr  = "Multi-State Output"
 	TrendLogStr          = "Trend Log"
+	EventLogStr          = "Event Log"
+	ChannelStr           = "Channel"
 	CharacterStringStr   = "Character String"
 )
 
@@ -54,7 +60,10 @@ var objTypeMap = map[ObjectType]string{
 	NotificationClass: NotificationClassStr,
 	MultiStateValue:   MultiStateValueStr,
 	MultiStateInput:   MultiStateInputStr,
+	MultiStateOutput:  MultiStateOutputStr,
 	TrendLog:          TrendLogStr,
+	EventLog:          EventLogStr,
+	Channel:           ChannelStr,
 	CharacterString:   CharacterStringStr,
 }
 
@@ -69,7 +78,11 @@ var objStrTypeMap = map[string]ObjectType{
 	FileStr:              File,
 	NotificationClassStr: NotificationClass,
 	MultiStateValueStr:   MultiStateValue,
+	MultiStateInputStr:   MultiStateInput,
+	MultiStateOutputStr:  MultiStateOutput,
 	TrendLogStr:          TrendLog,
+	EventLogStr:          EventLog,
+	ChannelStr:           Channel,
 	CharacterStringStr:   CharacterString,
 }
 
@@ -106,7 +119,11 @@ func (rp ReadMultipleProperty) String() string {
 			buff.WriteString(property.String(prop.Type))
 			buff.WriteString(fmt.Sprintf("[%v]", prop.ArrayIndex))
 			buff.WriteString(": ")
-			buff.WriteString(fmt.Sprintf("%v", prop.Data))
+			if prop.Error != nil {
+				buff.WriteString(prop.Error.Error())
+			} else {
+				buff.WriteString(fmt.Sprintf("%v", prop.Data))
+			}
 			buff.WriteString("\n")
 		}
 		buff.WriteString("\n")
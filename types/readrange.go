@@ -0,0 +1,127 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package types
+
+// RangeType selects how the items of a read range request are chosen.
+type RangeType int
+
+const (
+	// RangeAll reads every item in the list
+	RangeAll RangeType = iota
+	// RangeByPosition reads Count items starting at the 1 based position
+	// Reference
+	RangeByPosition
+	// RangeBySequenceNumber reads Count items starting at the item with the
+	// sequence number Reference
+	RangeBySequenceNumber
+	// RangeByTime reads Count items starting at the first item with a timestamp
+	// newer than Time
+	RangeByTime
+)
+
+// ReadRange is a request to read a range of items from a list property, such
+// as the Log_Buffer of a Trend Log or Event Log.
+type ReadRange struct {
+	Object     ObjectID
+	Property   uint32
+	ArrayIndex uint32
+
+	Range     RangeType
+	Reference uint32
+	Time      DateTime
+
+	// Count is the number of items to read. A negative count reads the items
+	// before the reference instead of after it.
+	Count int32
+}
+
+// ReadRangeAck is the result of a read range request.
+type ReadRangeAck struct {
+	Object     ObjectID
+	Property   uint32
+	ArrayIndex uint32
+
+	// Result flags
+	FirstItem bool
+	LastItem  bool
+	MoreItems bool
+
+	ItemCount uint32
+
+	// Items holds a LogRecord for Trend Logs, an EventLogRecord for Event
+	// Logs and application data for any other list.
+	Items []interface{}
+
+	// FirstSequenceNumber is the sequence number of the first item. It is only
+	// sent in replies to requests by sequence number or by time.
+	FirstSequenceNumber uint32
+}
+
+// LogRecord is an entry in the Log_Buffer of a Trend Log.
+type LogRecord struct {
+	Timestamp DateTime
+
+	// Value is one of LogStatus, bool, float32, Enumerated, uint32, int32,
+	// BitString, nil, LogFailure, TimeChange or, for any other datatype, a
+	// []interface{} of application data.
+	Value interface{}
+
+	// StatusFlags of the logged object, nil when they were not logged
+	StatusFlags BitString
+}
+
+// EventLogRecord is an entry in the Log_Buffer of an Event Log.
+type EventLogRecord struct {
+	Timestamp DateTime
+
+	// Value is one of LogStatus, TimeChange or EventNotification
+	Value interface{}
+}
+
+// LogStatus flags an event in the log itself instead of a logged value.
+type LogStatus BitString
+
+// Bits of LogStatus
+const (
+	LogDisabled    = 0
+	BufferPurged   = 1
+	LogInterrupted = 2
+)
+
+// TimeChange is the number of seconds the device's clock was changed by.
+type TimeChange float32
+
+// LogFailure is logged when the monitored value could not be read.
+type LogFailure struct {
+	Class ErrorClass
+	Code  ErrorCode
+}
@@ -0,0 +1,113 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	"github.com/alexbeltran/gobacnet/types"
+)
+
+// WhoHas finds the devices with ids between low and high that contain the
+// given object. The object is either its name as a string or its
+// types.ObjectID. Use WhoIsAll for both low and high to ask every device on
+// the network. Like WhoIs, replies are collected until no more arrive.
+func (c *Client) WhoHas(low, high int, object interface{}) ([]types.IHave, error) {
+	wh := types.WhoHas{
+		Low:  int32(low),
+		High: int32(high),
+	}
+	switch o := object.(type) {
+	case string:
+		wh.Name = o
+	case types.ObjectID:
+		wh.Object = &o
+	default:
+		return nil, fmt.Errorf("object must be a name or an object id, got %T", object)
+	}
+
+	var start, end int
+	if low == types.WhoIsAll || high == types.WhoIsAll {
+		start = 0
+		end = maxInt
+	} else {
+		start = low
+		end = high
+	}
+
+	dest := types.UDPToAddress(&net.UDPAddr{
+		IP:   c.broadcastAddress,
+		Port: DefaultPort,
+	})
+	dest.SetBroadcast(true)
+
+	// Run in parallel
+	errChan := make(chan error)
+	go func() {
+		errChan <- c.sendUnconfirmed(dest, func(enc *encoding.Encoder) error {
+			return enc.WhoHas(wh)
+		})
+	}()
+	values, err := c.utsm.Subscribe(start, end)
+	if err != nil {
+		return nil, err
+	}
+	err = <-errChan
+	if err != nil {
+		return nil, err
+	}
+
+	// Other replies, such as I-Am, are published to the same subscribers. Only
+	// keep the first reply of every device for the object we asked for.
+	unique := make(map[types.ObjectInstance]bool)
+	list := make([]types.IHave, 0)
+	for _, v := range values {
+		ih, ok := v.(types.IHave)
+		if !ok {
+			continue
+		}
+		if wh.Object != nil && ih.Object != *wh.Object {
+			continue
+		}
+		if wh.Object == nil && ih.Name != wh.Name {
+			continue
+		}
+		if unique[ih.Device.Instance] {
+			continue
+		}
+		unique[ih.Device.Instance] = true
+		list = append(list, ih)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+/*Copyright (C) 2017 Alex Beltran
+
+This program is free software; you can redistribute it and/or
+modify it under the terms of the GNU General Public License
+as published by the Free Software Foundation; either version 2
+of the License, or (at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program; if not, write to:
+The Free Software Foundation, Inc.
+59 Temple Place - Suite 330
+Boston, MA  02111-1307, USA.
+
+As a special exception, if other files instantiate templates or
+use macros or inline functions from this file, or you compile
+this file and link it with other works to produce a work based
+on this file, this file does not by itself cause the resulting
+work to be covered by the GNU General Public License. However
+the source code for this file must still be made available in
+accordance with section (3) of the GNU General Public License.
+
+This exception does not invalidate any other reasons why a work
+based on this file might be covered by the GNU General Public
+License.
+*/
+
+package gobacnet
+
+import (
+	"fmt"
+
+	"github.com/alexbeltran/gobacnet/encoding"
+	bactype "github.com/alexbeltran/gobacnet/types"
+)
+
+// WriteProperty writes value to a single property of an object in the given
+// device. Use ArrayAll as the array index to write the entire property. A
+// slice of type []interface{} is written as a list of values. Priority is the
+// command priority (1-16) used when writing commandable properties such as the
+// present value of an output. Use NoPriority for properties that are not
+// commandable. Writing a nil value relinquishes the command at that priority.
+func (c *Client) WriteProperty(dev bactype.Device, obj bactype.ObjectID, prop uint32, arrayIndex uint32, value interface{}, priority uint8) error {
+	if priority > bactype.MaxPriority {
+		return fmt.Errorf("priority must be between %d and %d, got %d", bactype.MinPriority, bactype.MaxPriority, priority)
+	}
+
+	wp := bactype.WritePropertyData{
+		Object: bactype.Object{
+			ID: obj,
+			Properties: []bactype.Property{
+				bactype.Property{
+					Type:       prop,
+					ArrayIndex: arrayIndex,
+					Data:       value,
+				},
+			},
+		},
+		Priority: priority,
+	}
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.WriteProperty(id, wp)
+	})
+}
+
+// WritePropertyMultiple writes to multiple properties of multiple objects in a
+// single request. Each property's Priority is used as its command priority.
+// If a write fails, a *WritePropertyMultipleError is returned which references
+// the first property that failed; all properties before it were written.
+func (c *Client) WritePropertyMultiple(dev bactype.Device, wp bactype.WriteMultipleProperty) error {
+	for _, obj := range wp.Objects {
+		for _, prop := range obj.Properties {
+			if prop.Priority > bactype.MaxPriority {
+				return fmt.Errorf("priority must be between %d and %d, got %d", bactype.MinPriority, bactype.MaxPriority, prop.Priority)
+			}
+		}
+	}
+
+	return c.simpleAckRequest(dev, func(enc *encoding.Encoder, id uint8) error {
+		return enc.WriteMultipleProperty(id, wp)
+	})
+}